@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 
 	"go.uber.org/zap"
 
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	lgf "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/factory"
 	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
 	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
 )
@@ -35,6 +38,16 @@ func runWithSlogLogger(logger *slg.Logger) {
 	logger.Info("Application finished")
 }
 
+// runWithLogger demonstrates usage of the backend-neutral logger
+func runWithLogger(logger lg.Logger) (err error) {
+	defer logger.EndWithError(&err)
+
+	logger.Info("Application started")
+
+	err = errors.New("database connection failed")
+	return fmt.Errorf("run: %w", err)
+}
+
 func main() {
 	// Using Zap logger
 	configZap := zlg.Config{
@@ -66,4 +79,20 @@ func main() {
 
 	slogLogger := slogFactory.GetLogger(ctx, slog.String("logger_type", "slog"), slog.String("request_id", "xyz789"))
 	runWithSlogLogger(slogLogger)
+
+	// Using backend-neutral logger, backend is chosen by config
+	config := lg.Config{
+		Backend:     lg.BackendZap, // "slog" or "zap"
+		ServiceName: "example-service",
+		Version:     "1.0.0",
+		LogLevel:    "info",
+	}
+	factory, err := lgf.NewFactory(config)
+	if err != nil {
+		panic(err)
+	}
+	defer factory.Close()
+
+	logger := factory.Get(ctx, lg.String("logger_type", config.Backend))
+	_ = runWithLogger(logger)
 }
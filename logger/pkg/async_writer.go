@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	AsyncBlock      = "block"
+	AsyncDropOldest = "drop_oldest"
+	AsyncDropNewest = "drop_newest"
+
+	defaultAsyncQueueSize = 1024
+)
+
+// AsyncConfig makes sinks write in a background goroutine. QueueSize bounds
+// the number of pending records (default 1024), Policy decides what happens
+// when the queue is full: AsyncBlock (default) waits, AsyncDropOldest and
+// AsyncDropNewest discard a record.
+type AsyncConfig struct {
+	QueueSize int
+	Policy    string
+}
+
+// AsyncStats are the metrics of an AsyncWriter.
+type AsyncStats struct {
+	Name          string
+	QueueDepth    int
+	QueueCapacity int
+	Dropped       uint64
+	WriteErrors   uint64
+}
+
+type asyncItem struct {
+	p       []byte
+	flushed chan struct{}
+}
+
+// AsyncWriter queues writes for a background goroutine writing them to the
+// wrapped writer in order. It is safe for concurrent use.
+type AsyncWriter struct {
+	name   string
+	w      io.Writer
+	policy string
+	queue  chan asyncItem
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	dropped     atomic.Uint64
+	writeErrors atomic.Uint64
+}
+
+func NewAsyncWriter(name string, w io.Writer, cfg AsyncConfig) (*AsyncWriter, error) {
+	switch cfg.Policy {
+	case "":
+		cfg.Policy = AsyncBlock
+	case AsyncBlock, AsyncDropOldest, AsyncDropNewest:
+	default:
+		return nil, fmt.Errorf("unknown async policy: %s", cfg.Policy)
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultAsyncQueueSize
+	}
+
+	a := &AsyncWriter{
+		name:   name,
+		w:      w,
+		policy: cfg.Policy,
+		queue:  make(chan asyncItem, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	return a, nil
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	for item := range a.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		if _, err := a.w.Write(item.p); err != nil {
+			a.writeErrors.Add(1)
+		}
+	}
+}
+
+// Write queues a copy of p, after Close it writes synchronously.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return a.w.Write(p)
+	}
+
+	item := asyncItem{p: append([]byte(nil), p...)}
+	switch a.policy {
+	case AsyncDropNewest:
+		select {
+		case a.queue <- item:
+		default:
+			a.dropped.Add(1)
+		}
+	case AsyncDropOldest:
+		for {
+			select {
+			case a.queue <- item:
+				return len(p), nil
+			default:
+			}
+			select {
+			case old := <-a.queue:
+				if old.flushed != nil {
+					close(old.flushed)
+				} else {
+					a.dropped.Add(1)
+				}
+			default:
+			}
+		}
+	default:
+		a.queue <- item
+	}
+	return len(p), nil
+}
+
+// Sync waits until the records queued before it are written and syncs the
+// wrapped writer when it supports it.
+func (a *AsyncWriter) Sync() error {
+	a.mu.RLock()
+	if !a.closed {
+		flushed := make(chan struct{})
+		a.queue <- asyncItem{flushed: flushed}
+		a.mu.RUnlock()
+		<-flushed
+	} else {
+		a.mu.RUnlock()
+	}
+
+	if s, ok := a.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close writes the queued records and stops the background goroutine. The
+// wrapped writer is not closed.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	<-a.done
+	return nil
+}
+
+func (a *AsyncWriter) Stats() AsyncStats {
+	return AsyncStats{
+		Name:          a.name,
+		QueueDepth:    len(a.queue),
+		QueueCapacity: cap(a.queue),
+		Dropped:       a.dropped.Load(),
+		WriteErrors:   a.writeErrors.Load(),
+	}
+}
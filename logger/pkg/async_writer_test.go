@@ -0,0 +1,181 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// gatedWriter records writes, each one waiting until the gate is opened.
+// started receives a value when a write starts waiting.
+type gatedWriter struct {
+	started chan struct{}
+	gate    chan struct{}
+	err     error
+
+	mu     sync.Mutex
+	writes []string
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), w.err
+}
+
+func (w *gatedWriter) written() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.writes...)
+}
+
+// fillQueue writes "0" and waits for the background goroutine to block on
+// it, then fills the queue of size 2 with "1" and "2".
+func fillQueue(t *testing.T, policy string) (*lg.AsyncWriter, *gatedWriter) {
+	t.Helper()
+	w := newGatedWriter()
+	a, err := lg.NewAsyncWriter("gated", w, lg.AsyncConfig{QueueSize: 2, Policy: policy})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = a.Close() })
+
+	_, err = a.Write([]byte("0"))
+	require.NoError(t, err)
+	<-w.started
+	for _, p := range []string{"1", "2"} {
+		_, err = a.Write([]byte(p))
+		require.NoError(t, err)
+	}
+	require.Equal(t, lg.AsyncStats{Name: "gated", QueueDepth: 2, QueueCapacity: 2}, a.Stats())
+	return a, w
+}
+
+func TestAsyncWriterPolicies(t *testing.T) {
+	tests := []struct {
+		policy  string
+		written []string
+		dropped uint64
+	}{
+		{lg.AsyncDropNewest, []string{"0", "1", "2"}, 2},
+		{lg.AsyncDropOldest, []string{"0", "3", "4"}, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.policy, func(t *testing.T) {
+			a, w := fillQueue(t, tc.policy)
+
+			for _, p := range []string{"3", "4"} {
+				n, err := a.Write([]byte(p))
+				require.NoError(t, err)
+				require.Equal(t, 1, n)
+			}
+			stats := a.Stats()
+			require.Equal(t, tc.dropped, stats.Dropped)
+			require.Equal(t, 2, stats.QueueDepth)
+
+			close(w.gate)
+			require.NoError(t, a.Close())
+			require.Equal(t, tc.written, w.written())
+			require.Equal(t, tc.dropped, a.Stats().Dropped)
+		})
+	}
+}
+
+func TestAsyncWriterBlock(t *testing.T) {
+	for _, policy := range []string{"", lg.AsyncBlock} {
+		t.Run("policy "+policy, func(t *testing.T) {
+			a, w := fillQueue(t, policy)
+
+			written := make(chan struct{})
+			go func() {
+				defer close(written)
+				_, _ = a.Write([]byte("3"))
+			}()
+			select {
+			case <-written:
+				t.Fatal("write to a full queue returned")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			close(w.gate)
+			<-written
+			require.NoError(t, a.Close())
+			require.Equal(t, []string{"0", "1", "2", "3"}, w.written())
+			require.Zero(t, a.Stats().Dropped)
+		})
+	}
+}
+
+func TestAsyncWriterClose(t *testing.T) {
+	w := newGatedWriter()
+	w.err = errors.New("disk full")
+	close(w.gate)
+	a, err := lg.NewAsyncWriter("gated", w, lg.AsyncConfig{})
+	require.NoError(t, err)
+
+	input := make([]byte, 1)
+	for _, p := range []string{"a", "b", "c"} {
+		copy(input, p)
+		_, err := a.Write(input)
+		require.NoError(t, err)
+	}
+	require.NoError(t, a.Sync())
+	require.Equal(t, []string{"a", "b", "c"}, w.written())
+
+	_, err = a.Write([]byte("d"))
+	require.NoError(t, err)
+	require.NoError(t, a.Close())
+	require.NoError(t, a.Close())
+	require.Equal(t, []string{"a", "b", "c", "d"}, w.written())
+	require.Equal(t, uint64(4), a.Stats().WriteErrors)
+
+	// After Close writes are synchronous and return the errors.
+	_, err = a.Write([]byte("e"))
+	require.EqualError(t, err, "disk full")
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, w.written())
+	require.NoError(t, a.Sync())
+}
+
+func TestAsyncWriterInvalidPolicy(t *testing.T) {
+	_, err := lg.NewAsyncWriter("gated", newGatedWriter(), lg.AsyncConfig{Policy: "spill"})
+	require.Error(t, err)
+}
+
+func TestAsyncSinks(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			sink, read := fileSink(t, lg.FormatJSON)
+			factory, err := b.newFactory(lg.Config{
+				Sinks: []lg.SinkConfig{sink},
+				Async: &lg.AsyncConfig{QueueSize: 16, Policy: lg.AsyncDropNewest},
+			})
+			require.NoError(t, err)
+
+			logger := factory.Named(context.Background(), "worker")
+			for range 5 {
+				logger.Info("queued")
+			}
+
+			stats := factory.(interface{ AsyncStats() []lg.AsyncStats }).AsyncStats()
+			require.Len(t, stats, 1)
+			require.Equal(t, sink.Destination, stats[0].Name)
+			require.Equal(t, 16, stats[0].QueueCapacity)
+
+			// Close writes the queued records.
+			require.NoError(t, factory.Close())
+			require.Equal(t, 5, strings.Count(read(), `"worker: queued"`))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"runtime"
+	"strings"
+)
+
+const (
+	maxCallerDepth = 8
+	gopanic        = "runtime.gopanic"
+)
+
+// CallerSkipper is implemented by the loggers of both backends. Libraries
+// wrapping a Logger use it to attribute records to their own callers.
+type CallerSkipper interface {
+	WithCallerSkip(skip int) Logger
+}
+
+// WithCallerSkip returns a logger attributing records to the code skip
+// frames above its usual caller, or l itself when it does not support it.
+func WithCallerSkip(l Logger, skip int) Logger {
+	if s, ok := l.(CallerSkipper); ok && skip != 0 {
+		return s.WithCallerSkip(skip)
+	}
+	return l
+}
+
+// CallerPC returns the program counter of the function skip frames above the
+// caller of CallerPC. Frames of the runtime are skipped. Below runtime.gopanic,
+// which runs the calls deferred when panicking, are the frames of the code that
+// panicked, which may be a callee of the function that deferred the call: the
+// first of them in the function of origin, the program counter a logger was
+// created at, is returned then, so that a deferred End is attributed to the
+// function it was deferred in. Without origin on the stack the first frame
+// outside the runtime is returned.
+func CallerPC(skip int, origin uintptr) uintptr {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			return pc
+		}
+		if fn.Name() == gopanic && origin != 0 {
+			if pc := panickingCallerPC(origin); pc != 0 {
+				return pc
+			}
+		}
+		if !strings.HasPrefix(fn.Name(), "runtime.") {
+			return pc
+		}
+	}
+	if n > 0 {
+		return pcs[0]
+	}
+	return 0
+}
+
+// panickingCallerPC returns the first frame below runtime.gopanic in the
+// function of origin, or 0.
+func panickingCallerPC(origin uintptr) uintptr {
+	fn := runtime.FuncForPC(origin - 1)
+	if fn == nil {
+		return 0
+	}
+	// Names rather than entries are compared: a function inlined into the one
+	// of origin shares its entry.
+	name := fn.Name()
+
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	panicking := false
+	for _, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		switch {
+		case fn == nil:
+		case fn.Name() == gopanic:
+			panicking = true
+		case panicking && fn.Name() == name:
+			return pc
+		}
+	}
+	return 0
+}
+
+// FunctionName returns the full name of the function of a program counter
+// returned by CallerPC.
+func FunctionName(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame.Function
+}
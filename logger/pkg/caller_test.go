@@ -0,0 +1,231 @@
+package logger_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
+)
+
+var errTest = errors.New("test error")
+
+type callerCase struct {
+	name string
+	log  func()
+}
+
+// callerLogger is the method set shared by lg.Logger, *slg.Logger and
+// *zlg.ZapLogger, F being their field type.
+type callerLogger[F, L any] interface {
+	WithFields(fields ...F) L
+	Debug(msg string, fields ...F)
+	Info(msg string, fields ...F)
+	Warning(msg string, fields ...F)
+	Error(msg string, fields ...F)
+	ErrorIn(funcName string, err error, fields ...F)
+	ErrorSQL(operation lg.SQLErrorType, table string, err error, fields ...F)
+	ErrorSQLSelect(table string, err error, fields ...F)
+	ErrorSQLInsert(table string, err error, fields ...F)
+	ErrorSQLUpdate(table string, err error, fields ...F)
+	ErrorSQLDelete(table string, err error, fields ...F)
+	ErrorSQLBegin(err error, fields ...F)
+	ErrorSQLCommit(err error, fields ...F)
+	ErrorSQLRollback(err error, fields ...F)
+	ErrorSQLDDL(table string, err error, fields ...F)
+	ErrorSQLMigration(migration string, err error, fields ...F)
+	ErrorSQLUpsert(table string, err error, fields ...F)
+	ErrorSQLConnection(err error, fields ...F)
+	End()
+	EndWithError(errPtr *error)
+}
+
+// callerCases returns the cases of every method of logger. get must be a
+// method value creating a logger at its caller, field is a field of F and
+// wrapper logs through a logger skipping one more frame.
+func callerCases[F any, L callerLogger[F, L]](logger L, get func(context.Context, ...F) L, field F, wrapper func(string)) []callerCase {
+	ctx := context.Background()
+	return []callerCase{
+		{"Debug", func() { logger.Debug("debug") }},
+		{"Info", func() { logger.Info("info") }},
+		{"Warning", func() { logger.Warning("warning") }},
+		{"Error", func() { logger.Error("error") }},
+		{"WithFields", func() { logger.WithFields(field).Info("info") }},
+		{"WithCallerSkip", func() { wrapper("wrapped") }},
+		{"ErrorIn", func() { logger.ErrorIn("call", errTest) }},
+		{"ErrorSQL", func() { logger.ErrorSQL(lg.SQLSelect, "users", errTest) }},
+		{"ErrorSQLSelect", func() { logger.ErrorSQLSelect("users", errTest) }},
+		{"ErrorSQLInsert", func() { logger.ErrorSQLInsert("users", errTest) }},
+		{"ErrorSQLUpdate", func() { logger.ErrorSQLUpdate("users", errTest) }},
+		{"ErrorSQLDelete", func() { logger.ErrorSQLDelete("users", errTest) }},
+		{"ErrorSQLBegin", func() { logger.ErrorSQLBegin(errTest) }},
+		{"ErrorSQLCommit", func() { logger.ErrorSQLCommit(errTest) }},
+		{"ErrorSQLRollback", func() { logger.ErrorSQLRollback(errTest) }},
+		{"ErrorSQLDDL", func() { logger.ErrorSQLDDL("users", errTest) }},
+		{"ErrorSQLMigration", func() { logger.ErrorSQLMigration("0001_init", errTest) }},
+		{"ErrorSQLUpsert", func() { logger.ErrorSQLUpsert("users", errTest) }},
+		{"ErrorSQLConnection", func() { logger.ErrorSQLConnection(errTest) }},
+		{"End", func() { defer logger.End() }},
+		{"EndWithError", func() { err := errTest; defer logger.EndWithError(&err) }},
+		{"End on panic", func() { defer recoverPanic(); l := get(ctx); defer l.End(); panic("boom") }},
+		{"End on callee panic", func() { defer recoverPanic(); l := get(ctx); defer l.End(); panicIn() }},
+		{"End on runtime panic", func() { defer recoverPanic(); l := get(ctx); defer l.End(); _ = errTestNil.Error() }},
+	}
+}
+
+var errTestNil error
+
+func recoverPanic() { _ = recover() }
+
+func panicIn() { panic("boom") }
+
+// records reads the JSON records written to a file sink since the last call.
+type records struct {
+	path string
+	seen int
+}
+
+func (r *records) next(t *testing.T) []map[string]any {
+	t.Helper()
+	file, err := os.Open(r.path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var all []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		all = append(all, record)
+	}
+	require.NoError(t, scanner.Err())
+
+	fresh := all[r.seen:]
+	r.seen = len(all)
+	return fresh
+}
+
+// newCallerFactory returns a factory of b writing JSON records read by the
+// returned records, with a logfmt sink checking the other encoders.
+func newCallerFactory(t *testing.T, b backend, cfg lg.Config) (lg.Factory, *records) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.json")
+	cfg.StacktraceOnError = true
+	cfg.Sinks = []lg.SinkConfig{
+		{Destination: path},
+		{Destination: filepath.Join(dir, "log.txt"), Format: lg.FormatLogfmt},
+	}
+	factory, err := b.newFactory(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, factory.Close()) })
+	return factory, &records{path: path}
+}
+
+// lineOf returns the line of a function literal written on a single line.
+func lineOf(fn func()) int {
+	pc := reflect.ValueOf(fn).Pointer()
+	_, line := runtime.FuncForPC(pc).FileLine(pc)
+	return line
+}
+
+func requireCaller(t *testing.T, recs *records, tc callerCase) {
+	t.Helper()
+	caller := fmt.Sprintf("pkg/caller_test.go:%d", lineOf(tc.log))
+
+	tc.log()
+	written := recs.next(t)
+	require.NotEmpty(t, written)
+	for _, record := range written {
+		require.Equal(t, caller, record[lg.CallerKey], record[lg.MessageKey])
+		require.NotContains(t, record, "file")
+		require.NotContains(t, record, "line")
+		if stack, ok := record[lg.StacktraceKey].(map[string]any); ok {
+			frame := stack["0"].(map[string]any)
+			require.Equal(t, float64(lineOf(tc.log)), frame["line"], record[lg.MessageKey])
+		}
+	}
+}
+
+func runCallerCases(t *testing.T, recs *records, tests []callerCase) {
+	t.Helper()
+	recs.next(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			requireCaller(t, recs, tc)
+		})
+	}
+}
+
+func TestLoggerCaller(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("slog", func(t *testing.T) {
+		factory, recs := newCallerFactory(t, backends[0], lg.Config{})
+		slogFactory := factory.(*slg.LoggerFactory)
+		logger := slogFactory.GetLogger(ctx)
+		wrapper := func(msg string) { logger.WithCallerSkip(1).Info(msg) }
+
+		tests := append([]callerCase{{"GetLogger", func() { slogFactory.GetLogger(ctx) }}},
+			callerCases(logger, slogFactory.GetLogger, slog.String("key", "value"), wrapper)...)
+		runCallerCases(t, recs, tests)
+	})
+
+	t.Run("zap", func(t *testing.T) {
+		factory, recs := newCallerFactory(t, backends[1], lg.Config{})
+		zapFactory := factory.(*zlg.ZapLoggerFactory)
+		logger := zapFactory.GetLogger(ctx)
+		wrapper := func(msg string) { logger.WithCallerSkip(1).Info(msg) }
+
+		tests := append([]callerCase{{"GetLogger", func() { zapFactory.GetLogger(ctx) }}},
+			callerCases(logger, zapFactory.GetLogger, zap.String("key", "value"), wrapper)...)
+		runCallerCases(t, recs, tests)
+	})
+}
+
+func TestFieldLoggerCaller(t *testing.T) {
+	ctx := context.Background()
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			factory, recs := newCallerFactory(t, b, lg.Config{})
+			logger := factory.Get(ctx)
+			wrapper := func(msg string) { lg.WithCallerSkip(logger, 1).Info(msg) }
+
+			tests := append([]callerCase{
+				{"Get", func() { factory.Get(ctx) }},
+				{"Named", func() { factory.Named(ctx, "worker") }},
+			}, callerCases(logger, factory.Get, lg.String("key", "value"), wrapper)...)
+			runCallerCases(t, recs, tests)
+		})
+	}
+}
+
+func TestConfigCallerSkip(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			factory, recs := newCallerFactory(t, b, lg.Config{CallerSkip: 1})
+
+			var logger lg.Logger
+			get := func() { logger = factory.Get(context.Background()) }
+			info := func(msg string) { logger.Info(msg) }
+
+			runCallerCases(t, recs, []callerCase{
+				{"Get", func() { get() }},
+				{"Info", func() { info("info") }},
+			})
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	consoleLevelWidth  = 5
+	consoleCallerWidth = 28
+	callerSeparator    = " - "
+
+	colorReset = "\x1b[0m"
+)
+
+var levelColors = map[string]string{
+	"DEBUG": "\x1b[35m",
+	"INFO":  "\x1b[34m",
+	"WARN":  "\x1b[33m",
+	"ERROR": "\x1b[31m",
+}
+
+// IsTerminal reports whether w is a terminal. Colors are also disabled by
+// a non-empty NO_COLOR environment variable.
+func IsTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// CallerPrefix is the text before the caller in console records: the
+// service name and version, if any.
+func CallerPrefix(serviceName, version string) string {
+	prefix := strings.TrimSpace(serviceName + " " + version)
+	if prefix == "" {
+		return ""
+	}
+	return prefix + callerSeparator
+}
+
+// ShortPath keeps the last directory and the file name of path.
+func ShortPath(path string) string {
+	idx := strings.LastIndexByte(path, '/')
+	if idx == -1 {
+		return path
+	}
+	if dir := strings.LastIndexByte(path[:idx], '/'); dir != -1 {
+		return path[dir+1:]
+	}
+	return path
+}
+
+// AppendConsoleLevel appends the upper case level padded to a fixed width,
+// colored when color is set.
+func AppendConsoleLevel(b []byte, level string, color bool) []byte {
+	level = strings.ToUpper(level)
+	code, known := levelColors[level]
+	if color && known {
+		b = append(b, code...)
+	}
+	b = appendPadded(b, level, consoleLevelWidth)
+	if color && known {
+		b = append(b, colorReset...)
+	}
+	return b
+}
+
+// AppendConsoleCaller appends caller padded to a fixed width.
+func AppendConsoleCaller(b []byte, caller string) []byte {
+	return appendPadded(b, caller, consoleCallerWidth)
+}
+
+func appendPadded(b []byte, s string, width int) []byte {
+	b = append(b, s...)
+	for i := len(s); i < width; i++ {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// AppendIndented appends every line of s indented with a tab, it is used for
+// the stack traces written below console records.
+func AppendIndented(b []byte, s string) []byte {
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" {
+			b = append(b, '\t')
+			b = append(b, line...)
+		}
+	}
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		b = append(b, '\n')
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package logger_test
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+var consoleTimePattern = regexp.MustCompile(`(?m)^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}(Z|[+-]\d{4}) `)
+
+// goldenConsole is the console output of logConsole, with %d the line of its
+// first call, the next calls on the following lines, and %s the level colors
+// and the color reset, empty without colors.
+const goldenConsole = `TIME %[2]sINFO %[6]s pkg/console_test.go:%[1]d       worker: start
+TIME %[3]sDEBUG%[6]s pkg/console_test.go:%[7]d       worker: debug space="a b" g.n=1
+TIME %[4]sWARN %[6]s pkg/console_test.go:%[8]d       worker: warn quoted__value_=1
+TIME %[5]sERROR%[6]s pkg/console_test.go:%[9]d       worker: failed error=boom
+`
+
+// logConsole logs the records of goldenConsole and returns the line of its
+// first call.
+func logConsole(factory lg.Factory) int {
+	line := lineOf(func() {})
+	logger := factory.Named(context.Background(), "worker")
+	logger.Debug("debug", lg.String("space", "a b"), lg.Group("g", lg.Int("n", 1)))
+	logger.Warning("warn", lg.Int(`quoted "value"`, 1))
+	logger.Error("failed", lg.Err(errors.New("boom")))
+	return line + 1
+}
+
+// The backends write the same console records, with colors forced on and off.
+func TestConsoleGolden(t *testing.T) {
+	colors := []any{"\x1b[34m", "\x1b[35m", "\x1b[33m", "\x1b[31m", "\x1b[0m"}
+	noColors := []any{"", "", "", "", ""}
+	for _, tc := range []struct {
+		color  string
+		colors []any
+	}{
+		{lg.ColorAlways, colors},
+		{lg.ColorNever, noColors},
+		// Files are not terminals.
+		{lg.ColorAuto, noColors},
+	} {
+		for _, b := range backends {
+			t.Run(b.name+"/"+cmp.Or(tc.color, "auto"), func(t *testing.T) {
+				sink, read := fileSink(t, lg.FormatText)
+				sink.Color = tc.color
+				factory, err := b.newFactory(lg.Config{Sinks: []lg.SinkConfig{sink}})
+				require.NoError(t, err)
+				line := logConsole(factory)
+				require.NoError(t, factory.Close())
+
+				want := fmt.Sprintf(goldenConsole, slices.Concat([]any{line}, tc.colors, []any{line + 1, line + 2, line + 3})...)
+				require.Equal(t, want, consoleTimePattern.ReplaceAllString(read(), "TIME "))
+			})
+		}
+	}
+}
+
+func TestConsoleInvalidColor(t *testing.T) {
+	for _, b := range backends {
+		_, err := b.newFactory(lg.Config{Sinks: []lg.SinkConfig{{Color: "sometimes"}}})
+		require.Error(t, err, b.name)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	require.NoError(t, err)
+	defer file.Close()
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	defer devNull.Close()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	for _, out := range []io.Writer{&bytes.Buffer{}, file, devNull, w} {
+		require.False(t, lg.IsTerminal(out), out)
+	}
+}
+
+func TestConsoleHelpers(t *testing.T) {
+	require.Equal(t, "svc 1.0 - ", lg.CallerPrefix("svc", "1.0"))
+	require.Equal(t, "svc - ", lg.CallerPrefix("svc", ""))
+	require.Empty(t, lg.CallerPrefix("", ""))
+
+	require.Equal(t, "words/norm.go", lg.ShortPath("/src/svc/words/norm.go"))
+	require.Equal(t, "norm.go", lg.ShortPath("norm.go"))
+
+	require.Equal(t, "WARN ", string(lg.AppendConsoleLevel(nil, "warn", false)))
+	require.Equal(t, "\x1b[33mWARN \x1b[0m", string(lg.AppendConsoleLevel(nil, "warn", true)))
+	require.Equal(t, "TRACE", string(lg.AppendConsoleLevel(nil, "trace", true)))
+	require.Equal(t, "norm.go:1"+"                   ", string(lg.AppendConsoleCaller(nil, "norm.go:1")))
+
+	require.Equal(t, "\tmain.main()\n\t\tmain.go:3\n", string(lg.AppendIndented(nil, "main.main()\n\tmain.go:3")))
+}
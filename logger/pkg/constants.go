@@ -4,10 +4,26 @@ const (
 	MsgStart              = "start"
 	MsgStartWithParams    = "start with params"
 	MsgEnd                = "end"
+	MsgEndWithError       = "end with error"
 	MsgPanicWasCatched    = "the panic was catched"
 	MsgCompletesWithError = "%s completes with error"
 	MsgSQLSelectWithError = "select from %s completes with error"
 	MsgSQLInsertWithError = "insert into %s completes with error"
 	MsgSQLUpdateWithError = "update %s completes with error"
 	MsgSQLDeleteWithError = "delete from %s completes with error"
+
+	MsgSQLBeginWithError      = "begin transaction completes with error"
+	MsgSQLCommitWithError     = "commit transaction completes with error"
+	MsgSQLRollbackWithError   = "rollback transaction completes with error"
+	MsgSQLDDLWithError        = "schema change of %s completes with error"
+	MsgSQLMigrationWithError  = "migration %s completes with error"
+	MsgSQLUpsertWithError     = "upsert into %s completes with error"
+	MsgSQLConnectionWithError = "connection acquisition completes with error"
+
+	MsgSQLOperationWithError = "SQL operation error on table %s"
+	MsgSQLSlowStatement      = "slow sql statement"
+	MsgReopenFailed          = "failed to reopen log file"
+	MsgRecordsDropped        = "records dropped by sampling"
+
+	MsgRPCCompletesWithCode = "rpc completes with code %s"
 )
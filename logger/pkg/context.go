@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+
+	traceparentVersion = "00"
+)
+
+// ContextExtractor returns fields bound to ctx that are added to every record
+// logged with it, see Config.ContextExtractors.
+type ContextExtractor func(ctx context.Context) []Field
+
+type contextFieldsKey struct{}
+
+// ExtractContextFields returns the trace fields of ctx, the fields stored by
+// WithContextFields and the fields returned by extractors, in this order.
+// A later field replaces an earlier one with the same key.
+func ExtractContextFields(ctx context.Context, extractors []ContextExtractor) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	fields := TraceFields(ctx)
+	fields = mergeFields(fields, FromContext(ctx)...)
+	for _, extractor := range extractors {
+		fields = mergeFields(fields, extractor(ctx)...)
+	}
+	return fields
+}
+
+// WithContextFields returns a copy of ctx carrying fields in addition to the ones
+// stored by outer calls. A field replaces an outer one with the same key in place.
+// Loggers created with the returned context add these fields to every record,
+// unless a field with the same key is passed to the logger explicitly.
+func WithContextFields(ctx context.Context, fields ...Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, mergeFields(FromContext(ctx), fields...))
+}
+
+// FromContext returns the fields stored in ctx by WithContextFields.
+func FromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]Field)
+	return fields
+}
+
+// mergeFields returns a new slice with fields added to base,
+// a field with a key already present in base replaces it.
+func mergeFields(base []Field, fields ...Field) []Field {
+	if len(fields) == 0 {
+		return base
+	}
+
+	merged := make([]Field, len(base), len(base)+len(fields))
+	copy(merged, base)
+	for _, field := range fields {
+		replaced := false
+		for i := range merged {
+			if merged[i].Key == field.Key {
+				merged[i] = field
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, field)
+		}
+	}
+	return merged
+}
+
+// TraceFields returns trace_id and span_id of the OpenTelemetry span context
+// carried by ctx, including one set by WithTraceparent.
+func TraceFields(ctx context.Context) []Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []Field{
+		String(TraceIDKey, sc.TraceID().String()),
+		String(SpanIDKey, sc.SpanID().String()),
+	}
+}
+
+// WithTraceparent parses a W3C traceparent header and stores it in ctx as
+// a remote OpenTelemetry span context. An invalid header leaves ctx unchanged.
+func WithTraceparent(ctx context.Context, traceparent string) (context.Context, error) {
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx, err
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc), nil
+}
+
+// Traceparent formats the span context of ctx as a W3C traceparent header,
+// it returns an empty string when ctx carries no valid span context.
+func Traceparent(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", traceparentVersion, sc.TraceID(), sc.SpanID(), sc.TraceFlags())
+}
+
+func ParseTraceparent(traceparent string) (trace.SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == traceparentVersion && len(parts) != 4) {
+		return trace.SpanContext{}, fmt.Errorf("invalid traceparent: %q", traceparent)
+	}
+
+	traceID, err := trace.TraceIDFromHex(parts[1])
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid traceparent trace id: %w", err)
+	}
+	spanID, err := trace.SpanIDFromHex(parts[2])
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid traceparent span id: %w", err)
+	}
+
+	var flags byte
+	if len(parts[3]) != 2 {
+		return trace.SpanContext{}, fmt.Errorf("invalid traceparent flags: %q", parts[3])
+	}
+	if _, err := fmt.Sscanf(parts[3], "%02x", &flags); err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid traceparent flags: %w", err)
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.TraceFlags(flags),
+		Remote:     true,
+	}), nil
+}
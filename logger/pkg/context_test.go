@@ -0,0 +1,61 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// Context fields are extracted once per logger and added to every record
+// of every sink, unless a field with the same key is passed explicitly.
+func TestContextFields(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			var calls atomic.Int32
+			tenant := func(context.Context) []lg.Field {
+				calls.Add(1)
+				return []lg.Field{lg.String("tenant", "acme")}
+			}
+			first, readFirst := fileSink(t, lg.FormatJSON)
+			second, readSecond := fileSink(t, lg.FormatJSON)
+			factory, err := b.newFactory(lg.Config{
+				Sinks:             []lg.SinkConfig{first, second},
+				ContextExtractors: []lg.ContextExtractor{tenant},
+			})
+			require.NoError(t, err)
+
+			ctx := lg.WithContextFields(traceContext(), lg.String("request_id", "r1"), lg.String("user", "jane"))
+			logger := factory.Named(ctx, "worker")
+			logger.Info("context")
+			logger.Info("explicit", lg.String("user", "ann"))
+			logger.WithFields(lg.String("request_id", "r2")).Info("fields")
+			logger.End()
+			require.NoError(t, factory.Close())
+			require.Equal(t, int32(1), calls.Load())
+
+			for _, output := range []string{readFirst(), readSecond()} {
+				records := jsonRecords(t, output)
+				require.Equal(t, []string{"start", "context", "explicit", "fields", "end"}, recordMessages(records))
+				for _, record := range records {
+					require.Equal(t, traceID.String(), record[lg.TraceIDKey])
+					require.Equal(t, spanID.String(), record[lg.SpanIDKey])
+					require.Equal(t, "acme", record["tenant"])
+				}
+				require.Equal(t, "jane", records[1]["user"])
+				require.Equal(t, "r1", records[1]["request_id"])
+				require.Equal(t, "ann", records[2]["user"])
+				require.Equal(t, "r2", records[3]["request_id"])
+
+				for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+					require.Equal(t, 1, strings.Count(line, `"request_id"`), line)
+					require.Equal(t, 1, strings.Count(line, `"user"`), line)
+				}
+			}
+		})
+	}
+}
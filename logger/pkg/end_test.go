@@ -0,0 +1,160 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// endRecord runs call with a logger of a factory of b with the slow call
+// threshold and returns its end record.
+func endRecord(t *testing.T, b backend, threshold time.Duration, call func(lg.Logger)) map[string]any {
+	t.Helper()
+	sink, read := fileSink(t, lg.FormatJSON)
+	factory, err := b.newFactory(lg.Config{Sinks: []lg.SinkConfig{sink}, SlowCallThreshold: threshold})
+	require.NoError(t, err)
+	call(factory.Named(context.Background(), "worker"))
+	require.NoError(t, factory.Close())
+
+	records := jsonRecords(t, read())
+	require.Len(t, records, 2)
+	return records[1]
+}
+
+func TestEndDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		sleep     time.Duration
+		level     string
+	}{
+		{"fast", time.Hour, 0, "info"},
+		{"slow", 10 * time.Millisecond, 20 * time.Millisecond, "warn"},
+		{"no threshold", 0, 20 * time.Millisecond, "info"},
+	}
+	for _, b := range backends {
+		for _, tc := range tests {
+			t.Run(b.name+"/"+tc.name, func(t *testing.T) {
+				record := endRecord(t, b, tc.threshold, func(logger lg.Logger) {
+					time.Sleep(tc.sleep)
+					logger.End()
+				})
+
+				require.Equal(t, "worker: "+lg.MsgEnd, record[lg.MessageKey])
+				require.Equal(t, tc.level, record[lg.LevelKey])
+				// Durations are written in milliseconds.
+				duration, ok := record[lg.DurationKey].(float64)
+				require.True(t, ok, record)
+				require.GreaterOrEqual(t, duration, float64(tc.sleep.Milliseconds()))
+				require.Less(t, duration, float64(tc.sleep.Milliseconds()+time.Second.Milliseconds()))
+				if tc.level == "warn" {
+					require.Equal(t, float64(tc.threshold.Milliseconds()), record[lg.SlowThresholdKey])
+				} else {
+					require.NotContains(t, record, lg.SlowThresholdKey)
+				}
+			})
+		}
+	}
+}
+
+func TestEndWithError(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    func(lg.Logger)
+		level   string
+		message string
+		err     any
+	}{
+		{
+			name:    "End",
+			call:    func(logger lg.Logger) { logger.End() },
+			level:   "info",
+			message: lg.MsgEnd,
+		},
+		{
+			name: "nil error",
+			call: func(logger lg.Logger) {
+				var err error
+				logger.EndWithError(&err)
+			},
+			level:   "info",
+			message: lg.MsgEnd,
+		},
+		{
+			name: "failed",
+			call: func(logger lg.Logger) {
+				err := errors.New("boom")
+				logger.EndWithError(&err)
+			},
+			level:   "error",
+			message: lg.MsgEndWithError,
+			err:     "boom",
+		},
+		{
+			name: "failed and slow",
+			call: func(logger lg.Logger) {
+				time.Sleep(20 * time.Millisecond)
+				err := errors.New("boom")
+				logger.EndWithError(&err)
+			},
+			level:   "error",
+			message: lg.MsgEndWithError,
+			err:     "boom",
+		},
+	}
+	for _, b := range backends {
+		for _, tc := range tests {
+			t.Run(b.name+"/"+tc.name, func(t *testing.T) {
+				record := endRecord(t, b, 10*time.Millisecond, tc.call)
+
+				require.Equal(t, "worker: "+tc.message, record[lg.MessageKey])
+				require.Equal(t, tc.level, record[lg.LevelKey])
+				require.Contains(t, record, lg.DurationKey)
+				require.Equal(t, tc.err, record[lg.ErrorKey])
+				if tc.err != nil {
+					require.NotContains(t, record, lg.SlowThresholdKey)
+				}
+			})
+		}
+	}
+}
+
+func endPanic(logger lg.Logger) {
+	defer recoverPanic()
+	defer logger.End()
+	panic("boom")
+}
+
+// The panic record of End is dropped like other records when its level is
+// disabled. Only zap has levels above error to disable it.
+func TestEndPanicLevel(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			sink, read := fileSink(t, lg.FormatJSON)
+			cfg := lg.Config{Sinks: []lg.SinkConfig{sink}}
+			if b.name == lg.BackendZap {
+				cfg.LevelRules = []string{"muted=dpanic"}
+			}
+			factory, err := b.newFactory(cfg)
+			require.NoError(t, err)
+			endPanic(factory.Named(context.Background(), "worker"))
+			endPanic(factory.Named(context.Background(), "muted"))
+			require.NoError(t, factory.Close())
+
+			want := []string{"worker: start", lg.MsgPanicWasCatched, "worker: end"}
+			if b.name != lg.BackendZap {
+				want = append(want, "muted: start", lg.MsgPanicWasCatched, "muted: end")
+			}
+			var messages []string
+			for _, record := range jsonRecords(t, read()) {
+				messages = append(messages, record[lg.MessageKey].(string))
+			}
+			require.Equal(t, want, messages)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ErrorKey       = "error"
+	ErrorTypeKey   = "error_type"
+	ErrorFieldsKey = "error_fields"
+	GRPCCodeKey    = "grpc.code"
+	SQLStateKey    = "sql.state"
+)
+
+// ErrorFielder is implemented by errors that carry their own fields, they are
+// logged in the error_fields group by EncodeError.
+type ErrorFielder interface {
+	LogFields() []Field
+}
+
+type grpcStatusError interface {
+	GRPCStatus() *status.Status
+}
+
+// sqlStateError is implemented by database driver errors, e.g. pgconn.PgError
+// of pgx and pq.Error of lib/pq.
+type sqlStateError interface {
+	SQLState() string
+}
+
+// EncodeError returns the fields describing err, shared by both backends:
+//   - error: the message of err;
+//   - error_type: the Go type of err;
+//   - error_chain: message and type of every wrapped error, see WalkError;
+//   - grpc.code: the code of the first gRPC status in the chain;
+//   - sql.state: the SQLSTATE code of the first database error in the chain;
+//   - error_fields: fields of the errors implementing ErrorFielder, the
+//     outermost error wins on duplicate keys.
+func EncodeError(err error) []Field {
+	if err == nil {
+		return nil
+	}
+
+	fields := []Field{
+		String(ErrorKey, err.Error()),
+		String(ErrorTypeKey, errorType(err)),
+	}
+
+	var chain, errorFields []Field
+	var code *status.Status
+	WalkError(err, func(err error) {
+		chain = append(chain, Group(strconv.Itoa(len(chain)),
+			String("message", err.Error()),
+			String("type", errorType(err))))
+
+		if e, ok := err.(grpcStatusError); ok && code == nil {
+			code = e.GRPCStatus()
+		}
+		if e, ok := err.(ErrorFielder); ok {
+			errorFields = mergeFields(e.LogFields(), errorFields...)
+		}
+	})
+
+	if len(chain) > 1 {
+		fields = append(fields, Group(ErrorChainKey, chain...))
+	}
+	if code != nil {
+		fields = append(fields, String(GRPCCodeKey, code.Code().String()))
+	}
+	if state := SQLState(err); state != "" {
+		fields = append(fields, String(SQLStateKey, state))
+	}
+	if len(errorFields) > 0 {
+		fields = append(fields, Group(ErrorFieldsKey, errorFields...))
+	}
+	return fields
+}
+
+// WalkError calls visit for err and every error it wraps, depth first,
+// following both Unwrap() error and Unwrap() []error (errors.Join).
+func WalkError(err error, visit func(error)) {
+	if err == nil {
+		return
+	}
+	visit(err)
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			WalkError(inner, visit)
+		}
+	case interface{ Unwrap() error }:
+		WalkError(e.Unwrap(), visit)
+	}
+}
+
+// SQLState returns the SQLSTATE code of the first error in the chain of err
+// implementing SQLState() string, or "" when there is none.
+func SQLState(err error) string {
+	var state string
+	WalkError(err, func(err error) {
+		if e, ok := err.(sqlStateError); ok && state == "" {
+			state = e.SQLState()
+		}
+	})
+	return state
+}
+
+func errorType(err error) string {
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,147 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// fieldsError carries its own fields and wraps err.
+type fieldsError struct {
+	fields []lg.Field
+	err    error
+}
+
+func (e fieldsError) Error() string         { return "fields: " + e.err.Error() }
+func (e fieldsError) Unwrap() error         { return e.err }
+func (e fieldsError) LogFields() []lg.Field { return e.fields }
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "duplicate key" }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// errorKeys are the keys written by lg.EncodeError.
+var errorKeys = []string{lg.ErrorKey, lg.ErrorTypeKey, lg.ErrorChainKey, lg.GRPCCodeKey, lg.SQLStateKey, lg.ErrorFieldsKey}
+
+// chainEntry is an element of the error_chain group.
+func chainEntry(message, typ string) map[string]any {
+	return map[string]any{"message": message, "type": typ}
+}
+
+func TestEncodeError(t *testing.T) {
+	base := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want map[string]any
+	}{
+		{
+			name: "nil",
+			want: map[string]any{},
+		},
+		{
+			name: "plain",
+			err:  base,
+			want: map[string]any{lg.ErrorKey: "boom", lg.ErrorTypeKey: "*errors.errorString"},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("load: %w", base),
+			want: map[string]any{
+				lg.ErrorKey:     "load: boom",
+				lg.ErrorTypeKey: "*fmt.wrapError",
+				lg.ErrorChainKey: map[string]any{
+					"0": chainEntry("load: boom", "*fmt.wrapError"),
+					"1": chainEntry("boom", "*errors.errorString"),
+				},
+			},
+		},
+		{
+			name: "joined",
+			err:  errors.Join(base, errors.New("retry")),
+			want: map[string]any{
+				lg.ErrorKey:     "boom\nretry",
+				lg.ErrorTypeKey: "*errors.joinError",
+				lg.ErrorChainKey: map[string]any{
+					"0": chainEntry("boom\nretry", "*errors.joinError"),
+					"1": chainEntry("boom", "*errors.errorString"),
+					"2": chainEntry("retry", "*errors.errorString"),
+				},
+			},
+		},
+		{
+			name: "error fields",
+			err: fieldsError{
+				fields: []lg.Field{lg.Int("id", 1), lg.String("op", "outer")},
+				err:    fieldsError{fields: []lg.Field{lg.Int("id", 2), lg.String("table", "users")}, err: base},
+			},
+			want: map[string]any{
+				lg.ErrorKey:     "fields: fields: boom",
+				lg.ErrorTypeKey: "logger_test.fieldsError",
+				lg.ErrorChainKey: map[string]any{
+					"0": chainEntry("fields: fields: boom", "logger_test.fieldsError"),
+					"1": chainEntry("fields: boom", "logger_test.fieldsError"),
+					"2": chainEntry("boom", "*errors.errorString"),
+				},
+				lg.ErrorFieldsKey: map[string]any{"id": 1.0, "op": "outer", "table": "users"},
+			},
+		},
+		{
+			name: "grpc status",
+			err:  fmt.Errorf("check: %w", status.Error(codes.Unavailable, "down")),
+			want: map[string]any{
+				lg.ErrorKey:     "check: rpc error: code = Unavailable desc = down",
+				lg.ErrorTypeKey: "*fmt.wrapError",
+				lg.ErrorChainKey: map[string]any{
+					"0": chainEntry("check: rpc error: code = Unavailable desc = down", "*fmt.wrapError"),
+					"1": chainEntry("rpc error: code = Unavailable desc = down", "*status.Error"),
+				},
+				lg.GRPCCodeKey: "Unavailable",
+			},
+		},
+		{
+			name: "sql state",
+			err:  errors.Join(base, fmt.Errorf("insert: %w", sqlStateError("23505"))),
+			want: map[string]any{
+				lg.ErrorKey:     "boom\ninsert: duplicate key",
+				lg.ErrorTypeKey: "*errors.joinError",
+				lg.ErrorChainKey: map[string]any{
+					"0": chainEntry("boom\ninsert: duplicate key", "*errors.joinError"),
+					"1": chainEntry("boom", "*errors.errorString"),
+					"2": chainEntry("insert: duplicate key", "*fmt.wrapError"),
+					"3": chainEntry("duplicate key", "logger_test.sqlStateError"),
+				},
+				lg.SQLStateKey: "23505",
+			},
+		},
+	}
+	for _, b := range backends {
+		for _, tc := range tests {
+			t.Run(b.name+"/"+tc.name, func(t *testing.T) {
+				sink, read := fileSink(t, lg.FormatJSON)
+				factory, err := b.newFactory(lg.Config{Sinks: []lg.SinkConfig{sink}})
+				require.NoError(t, err)
+				factory.Named(context.Background(), "worker").ErrorIn("load", tc.err)
+				require.NoError(t, factory.Close())
+
+				records := jsonRecords(t, read())
+				require.Len(t, records, 2)
+				got := map[string]any{}
+				for _, key := range errorKeys {
+					if value, ok := records[1][key]; ok {
+						got[key] = value
+					}
+				}
+				require.Equal(t, tc.want, got)
+			})
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
+)
+
+// NewFactory creates a factory for the backend selected by cfg.Backend.
+// An empty backend defaults to slog.
+func NewFactory(cfg lg.Config) (lg.Factory, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", lg.BackendSlog:
+		factory, err := slg.NewLoggerFactory(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return factory, nil
+	case lg.BackendZap:
+		factory, err := zlg.NewZapLoggerFactory(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return factory, nil
+	default:
+		return nil, fmt.Errorf("unknown logger backend: %s", cfg.Backend)
+	}
+}
@@ -0,0 +1,48 @@
+package logger_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	lgf "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/factory"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
+)
+
+func TestNewFactory(t *testing.T) {
+	tests := []struct {
+		backend string
+		want    lg.Factory
+	}{
+		{"", &slg.LoggerFactory{}},
+		{lg.BackendSlog, &slg.LoggerFactory{}},
+		{"Slog", &slg.LoggerFactory{}},
+		{lg.BackendZap, &zlg.ZapLoggerFactory{}},
+		{"ZAP", &zlg.ZapLoggerFactory{}},
+	}
+	for _, tc := range tests {
+		factory, err := lgf.NewFactory(lg.Config{
+			Backend:  tc.backend,
+			LogLevel: "info",
+			Sinks:    []lg.SinkConfig{{Destination: filepath.Join(t.TempDir(), "out.log")}},
+		})
+		require.NoError(t, err, tc.backend)
+		require.IsType(t, tc.want, factory, tc.backend)
+		require.Equal(t, "info", factory.(lg.LevelController).Level())
+		require.NoError(t, factory.Close())
+	}
+}
+
+func TestNewFactoryInvalid(t *testing.T) {
+	_, err := lgf.NewFactory(lg.Config{Backend: "logrus"})
+	require.EqualError(t, err, "unknown logger backend: logrus")
+
+	for _, backend := range []string{lg.BackendSlog, lg.BackendZap} {
+		_, err := lgf.NewFactory(lg.Config{Backend: backend, LogLevel: "verbose"})
+		require.Error(t, err, backend)
+		require.Contains(t, err.Error(), "verbose", backend)
+	}
+}
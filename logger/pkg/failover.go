@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryAfter    = time.Second
+	defaultMaxRetryAfter = time.Minute
+)
+
+// FailoverConfig isolates the sinks of a factory from each other.
+type FailoverConfig struct {
+	// Concurrent writes every record to the sinks in parallel.
+	Concurrent bool
+
+	// MaxFailures consecutive errors disable a sink for RetryAfter
+	// (default 1s), doubled after every failed retry up to MaxRetryAfter
+	// (default 1m). A successful retry enables the sink again. Zero never
+	// disables sinks.
+	MaxFailures   int
+	RetryAfter    time.Duration
+	MaxRetryAfter time.Duration
+
+	// Fallback writes the records a sink failed on or skipped while
+	// disabled, e.g. to stderr. Nil drops them.
+	Fallback *SinkConfig
+
+	// OnError is called with the destination and the error of a failing sink.
+	OnError func(destination string, err error)
+}
+
+// SinkFailures tracks the consecutive failures of a sink and disables it
+// after maxFailures of them, see FailoverConfig. It is safe for concurrent use.
+type SinkFailures struct {
+	maxFailures   int
+	retryAfter    time.Duration
+	maxRetryAfter time.Duration
+
+	mu            sync.Mutex
+	failures      int
+	delay         time.Duration
+	disabledUntil time.Time
+}
+
+// NewSinkFailures applies the defaults of FailoverConfig to zero durations.
+func NewSinkFailures(maxFailures int, retryAfter, maxRetryAfter time.Duration) *SinkFailures {
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+	if maxRetryAfter < retryAfter {
+		maxRetryAfter = max(defaultMaxRetryAfter, retryAfter)
+	}
+	return &SinkFailures{maxFailures: maxFailures, retryAfter: retryAfter, maxRetryAfter: maxRetryAfter}
+}
+
+// Enabled reports whether the sink is not disabled at now.
+func (s *SinkFailures) Enabled(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !now.Before(s.disabledUntil)
+}
+
+// Record counts err, a nil error resets the failures and the retry delay.
+func (s *SinkFailures) Record(err error, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.failures = 0
+		s.delay = 0
+		return
+	}
+
+	s.failures++
+	if s.maxFailures <= 0 || s.failures < s.maxFailures {
+		return
+	}
+	if s.delay == 0 {
+		s.delay = s.retryAfter
+	} else {
+		s.delay = min(2*s.delay, s.maxRetryAfter)
+	}
+	s.disabledUntil = now.Add(s.delay)
+}
+
+// FallbackConfig returns the Failover fallback with defaults applied, nil
+// without one.
+func (c Config) FallbackConfig() (*SinkConfig, error) {
+	if c.Failover == nil || c.Failover.Fallback == nil {
+		return nil, nil
+	}
+	sink, err := c.Failover.Fallback.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &sink, nil
+}
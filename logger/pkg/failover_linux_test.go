@@ -0,0 +1,68 @@
+package logger_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// failingDestination fails every write with ENOSPC.
+const failingDestination = "/dev/full"
+
+func TestFailover(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		for _, b := range backends {
+			t.Run(fmt.Sprintf("%s/concurrent=%t", b.name, concurrent), func(t *testing.T) {
+				healthy, readHealthy := fileSink(t, lg.FormatJSON)
+				fallback, readFallback := fileSink(t, lg.FormatJSON)
+				var mu sync.Mutex
+				var failed []string
+				factory, err := b.newFactory(lg.Config{
+					Sinks: []lg.SinkConfig{{Destination: failingDestination}, healthy},
+					Failover: &lg.FailoverConfig{
+						Concurrent:  concurrent,
+						MaxFailures: 2,
+						RetryAfter:  time.Hour,
+						Fallback:    &fallback,
+						OnError: func(destination string, err error) {
+							mu.Lock()
+							defer mu.Unlock()
+							failed = append(failed, destination)
+						},
+					},
+				})
+				require.NoError(t, err)
+
+				logger := factory.Named(context.Background(), "worker")
+				logger.Info("failed")
+				logger.Info("skipped")
+				// Syncing the failing sink fails too.
+				_ = factory.Close()
+
+				// The failing sink is disabled after the start record and
+				// "failed", the healthy one gets every record.
+				require.Equal(t, []string{failingDestination, failingDestination}, failed)
+				want := []string{"start", "failed", "skipped"}
+				require.Equal(t, want, recordMessages(jsonRecords(t, readHealthy())))
+				require.Equal(t, want, recordMessages(jsonRecords(t, readFallback())))
+			})
+		}
+	}
+}
+
+func TestFailoverInvalidFallback(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			_, err := b.newFactory(lg.Config{Failover: &lg.FailoverConfig{
+				Fallback: &lg.SinkConfig{Format: "xml"},
+			}})
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import "time"
+
+// Field is a backend-neutral key-value pair converted by each backend
+// into its native representation (slog.Attr or zap.Field).
+type Field struct {
+	Key   string
+	Value any
+}
+
+// GroupValue is the value of a Field created by Group.
+type GroupValue []Field
+
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, Value: GroupValue(fields)}
+}
@@ -0,0 +1,92 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
+)
+
+type backend struct {
+	name       string
+	newFactory func(lg.Config) (lg.Factory, error)
+}
+
+var backends = []backend{
+	{"slog", func(cfg lg.Config) (lg.Factory, error) { return slg.NewLoggerFactory(cfg) }},
+	{"zap", func(cfg lg.Config) (lg.Factory, error) { return zlg.NewZapLoggerFactory(cfg) }},
+}
+
+// newFactory returns a factory of b writing JSON to a temporary file and
+// the function returning its records, to be called after Close.
+func newFactory(t *testing.T, b backend, cfg lg.Config) (lg.Factory, func() []map[string]any) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.log")
+	cfg.Sinks = []lg.SinkConfig{{Destination: path, Format: lg.FormatJSON}}
+	factory, err := b.newFactory(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = factory.Close() })
+
+	return factory, func() []map[string]any {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			record := map[string]any{}
+			require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+			record[rawKey] = line
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+// rawKey holds the JSON line of a record returned by newFactory.
+const rawKey = "_raw"
+
+// findRecord returns the record with a message ending with suffix.
+func findRecord(t *testing.T, records []map[string]any, suffix string) map[string]any {
+	t.Helper()
+	for _, record := range records {
+		if msg, _ := record[lg.MessageKey].(string); strings.HasSuffix(msg, suffix) {
+			return record
+		}
+	}
+	require.Failf(t, "record not found", "no record ending with %q", suffix)
+	return nil
+}
+
+// serve starts a server with the given options over an in-memory listener,
+// registers its services with register and returns a connection to it.
+func serve(t *testing.T, register func(*grpc.Server), serverOpts []grpc.ServerOption, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(serverOpts...)
+	register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufconn", dialOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const TargetKey = "grpc.target"
+
+// UnaryClientInterceptor logs each call with its duration and status code and
+// sends the request id and the trace context of ctx to the server. A context
+// without request id gets a new one, see WithRequestID.
+func UnaryClientInterceptor(factory lg.Factory) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx = lg.WithContextFields(outgoingContext(ctx), lg.String(MethodKey, method))
+
+		fields := append(deadlineFields(ctx), lg.String(TargetKey, cc.Target()))
+		fields = append(fields, sizeFields(RequestSizeKey, req)...)
+		logger := factory.Named(ctx, method, fields...)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCompletion(logger, status.Code(err), err, completionFields(status.Code(err), start)...)
+		return err
+	}
+}
+
+// StreamClientInterceptor is the streaming counterpart of UnaryClientInterceptor.
+// The completion is logged when the stream ends with an error or io.EOF,
+// so streams that are not read to the end are logged at start only.
+func StreamClientInterceptor(factory lg.Factory) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx = lg.WithContextFields(outgoingContext(ctx), lg.String(MethodKey, method))
+
+		fields := append(deadlineFields(ctx),
+			lg.String(TargetKey, cc.Target()),
+			lg.String(StreamKey, streamType(desc.ClientStreams, desc.ServerStreams)))
+		logger := factory.Named(ctx, method, fields...)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCompletion(logger, status.Code(err), err, completionFields(status.Code(err), start)...)
+			return nil, err
+		}
+
+		return &loggingClientStream{ClientStream: cs, logger: logger, start: start}, nil
+	}
+}
+
+type loggingClientStream struct {
+	grpc.ClientStream
+	logger   lg.Logger
+	start    time.Time
+	sent     atomic.Int64
+	received atomic.Int64
+	once     sync.Once
+}
+
+func (s *loggingClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	} else if !errors.Is(err, io.EOF) {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.received.Add(1)
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) finish(err error) {
+	s.once.Do(func() {
+		fields := append(completionFields(status.Code(err), s.start),
+			lg.Int64(SentKey, s.sent.Load()),
+			lg.Int64(ReceivedKey, s.received.Load()))
+		logCompletion(s.logger, status.Code(err), err, fields...)
+	})
+}
@@ -0,0 +1,191 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	glg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/grpc_logger"
+)
+
+const (
+	target      = "passthrough:///bufconn"
+	traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// newLoggingClient serves healthServer behind interceptors recording the
+// incoming metadata of each call, and returns a client logging with the
+// client interceptors to its own factory. The returned function returns the
+// records of the client and of the server.
+func newLoggingClient(t *testing.T, b backend) (healthpb.HealthClient, <-chan metadata.MD, func() (client, server []map[string]any)) {
+	t.Helper()
+	incoming := make(chan metadata.MD, 10)
+	record := func(ctx context.Context) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		incoming <- md
+	}
+
+	serverFactory, serverRecords := newFactory(t, b, lg.Config{LogLevel: "info"})
+	factory, records := newFactory(t, b, lg.Config{LogLevel: "info"})
+	conn := serve(t, func(s *grpc.Server) { healthpb.RegisterHealthServer(s, healthServer{}) },
+		[]grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(
+				func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+					record(ctx)
+					return handler(ctx, req)
+				},
+				glg.UnaryServerInterceptor(serverFactory)),
+			grpc.ChainStreamInterceptor(
+				func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+					record(ss.Context())
+					return handler(srv, ss)
+				},
+				glg.StreamServerInterceptor(serverFactory)),
+		},
+		grpc.WithChainUnaryInterceptor(glg.UnaryClientInterceptor(factory)),
+		grpc.WithChainStreamInterceptor(glg.StreamClientInterceptor(factory)))
+
+	return healthpb.NewHealthClient(conn), incoming, func() ([]map[string]any, []map[string]any) {
+		t.Helper()
+		require.NoError(t, factory.Close())
+		require.NoError(t, serverFactory.Close())
+		return records(), serverRecords()
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, incoming, records := newLoggingClient(t, b)
+			req := &healthpb.HealthCheckRequest{Service: "words"}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			_, err := client.Check(ctx, req)
+			require.NoError(t, err)
+
+			md := <-incoming
+			requestID := md.Get(glg.RequestIDHeader)
+			require.Len(t, requestID, 1)
+			require.Regexp(t, requestIDPattern, requestID[0])
+			require.Empty(t, md.Get(glg.TraceparentHeader))
+
+			all, _ := records()
+			start := findRecord(t, all, lg.MsgStartWithParams)
+			require.Equal(t, checkMethod, start[glg.MethodKey])
+			require.Equal(t, target, start[glg.TargetKey])
+			require.Equal(t, float64(proto.Size(req)), start[glg.RequestSizeKey])
+			require.Contains(t, start, glg.DeadlineKey)
+			require.Equal(t, requestID[0], start[glg.RequestIDKey])
+
+			record := findRecord(t, all, completesOK)
+			require.Equal(t, "info", record[lg.LevelKey])
+			require.Equal(t, checkMethod, record[glg.MethodKey])
+			require.Equal(t, "OK", record[glg.CodeKey])
+			require.Contains(t, record, glg.DurationKey)
+			require.Equal(t, requestID[0], record[glg.RequestIDKey])
+			require.NotContains(t, record, lg.TraceIDKey)
+		})
+	}
+}
+
+func TestUnaryClientInterceptorPropagation(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, incoming, records := newLoggingClient(t, b)
+			ctx, err := lg.WithTraceparent(glg.WithRequestID(context.Background(), "req-1"), traceparent)
+			require.NoError(t, err)
+			_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "words"})
+			require.NoError(t, err)
+
+			md := <-incoming
+			require.Equal(t, []string{"req-1"}, md.Get(glg.RequestIDHeader))
+			require.Equal(t, []string{traceparent}, md.Get(glg.TraceparentHeader))
+
+			all, server := records()
+			record := findRecord(t, all, completesOK)
+			require.Equal(t, "req-1", record[glg.RequestIDKey])
+			require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record[lg.TraceIDKey])
+			require.Equal(t, "00f067aa0ba902b7", record[lg.SpanIDKey])
+
+			// The server interceptors log under the same request id and trace.
+			for _, record := range server {
+				require.Equal(t, "req-1", record[glg.RequestIDKey], record[lg.MessageKey])
+				require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record[lg.TraceIDKey], record[lg.MessageKey])
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptorErrors(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, _, records := newLoggingClient(t, b)
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+			require.Equal(t, codes.NotFound, status.Code(err))
+			_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "down"})
+			require.Equal(t, codes.Unavailable, status.Code(err))
+			all, _ := records()
+
+			missing := findRecord(t, all, "rpc completes with code NotFound")
+			require.Equal(t, "warn", missing[lg.LevelKey])
+			require.Equal(t, "NotFound", missing[glg.CodeKey])
+			require.Equal(t, "rpc error: code = NotFound desc = unknown service", missing[lg.ErrorKey])
+
+			down := findRecord(t, all, "rpc completes with code Unavailable")
+			require.Equal(t, "error", down[lg.LevelKey])
+			require.Equal(t, "Unavailable", down[glg.CodeKey])
+			require.NotEqual(t, missing[glg.RequestIDKey], down[glg.RequestIDKey])
+		})
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, incoming, records := newLoggingClient(t, b)
+			receive := func(service string) error {
+				stream, err := client.Watch(glg.WithRequestID(context.Background(), service), &healthpb.HealthCheckRequest{Service: service})
+				require.NoError(t, err)
+				for {
+					if _, err := stream.Recv(); err != nil {
+						return err
+					}
+				}
+			}
+			require.True(t, errors.Is(receive("words"), io.EOF))
+			require.Equal(t, []string{"words"}, (<-incoming).Get(glg.RequestIDHeader))
+			require.Equal(t, codes.Internal, status.Code(receive("panic")))
+			all, _ := records()
+
+			start := findRecord(t, all, lg.MsgStartWithParams)
+			require.Equal(t, watchMethod, start[glg.MethodKey])
+			require.Equal(t, target, start[glg.TargetKey])
+			require.Equal(t, "server", start[glg.StreamKey])
+
+			record := findRecord(t, all, completesOK)
+			require.Equal(t, "words", record[glg.RequestIDKey])
+			require.Equal(t, 1.0, record[glg.SentKey])
+			require.Equal(t, 3.0, record[glg.ReceivedKey])
+			require.Contains(t, record, glg.DurationKey)
+
+			failed := findRecord(t, all, "rpc completes with code Internal")
+			require.Equal(t, "panic", failed[glg.RequestIDKey])
+			require.Equal(t, "error", failed[lg.LevelKey])
+			require.Equal(t, 3.0, failed[glg.ReceivedKey])
+		})
+	}
+}
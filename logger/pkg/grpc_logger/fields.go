@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const (
+	MethodKey      = "grpc.method"
+	PeerKey        = "grpc.peer"
+	DeadlineKey    = "grpc.deadline"
+	RequestSizeKey = "grpc.request_size"
+	CodeKey        = lg.GRPCCodeKey
+	DurationKey    = "grpc.duration"
+	StreamKey      = "grpc.stream"
+	SentKey        = "grpc.sent_messages"
+	ReceivedKey    = "grpc.received_messages"
+	PanicKey       = "panic"
+	StacktraceKey  = lg.StacktraceKey
+)
+
+type loggerKey struct{}
+
+// LoggerFromContext returns the per-RPC logger stored by the server interceptors.
+func LoggerFromContext(ctx context.Context) (lg.Logger, bool) {
+	logger, ok := ctx.Value(loggerKey{}).(lg.Logger)
+	return logger, ok
+}
+
+func contextWithLogger(ctx context.Context, logger lg.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+func peerFields(ctx context.Context) []lg.Field {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return []lg.Field{lg.String(PeerKey, p.Addr.String())}
+	}
+	return nil
+}
+
+func deadlineFields(ctx context.Context) []lg.Field {
+	if deadline, ok := ctx.Deadline(); ok {
+		return []lg.Field{lg.Time(DeadlineKey, deadline)}
+	}
+	return nil
+}
+
+func sizeFields(key string, msg any) []lg.Field {
+	if m, ok := msg.(proto.Message); ok {
+		return []lg.Field{lg.Int(key, proto.Size(m))}
+	}
+	return nil
+}
+
+func completionFields(code codes.Code, start time.Time) []lg.Field {
+	return []lg.Field{
+		lg.String(CodeKey, code.String()),
+		lg.Duration(DurationKey, time.Since(start)),
+	}
+}
+
+// logCompletion logs the result of a call at a level depending on its status code:
+// Info for OK, Warning for errors caused by the client, Error otherwise.
+// The error is encoded with lg.EncodeError like the errors of the backends,
+// without its gRPC code which is already among the fields.
+func logCompletion(logger lg.Logger, code codes.Code, err error, fields ...lg.Field) {
+	msg := fmt.Sprintf(lg.MsgRPCCompletesWithCode, code)
+	for _, field := range lg.EncodeError(err) {
+		if field.Key != CodeKey {
+			fields = append(fields, field)
+		}
+	}
+
+	switch code {
+	case codes.OK:
+		logger.Info(msg, fields...)
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		logger.Warning(msg, fields...)
+	default:
+		logger.Error(msg, fields...)
+	}
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	adminpb "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/proto/admin"
+)
+
+type levelAdminServer struct {
+	adminpb.UnimplementedLevelAdminServer
+	admin *lg.LevelAdmin
+}
+
+// RegisterLevelAdmin registers the LevelAdmin gRPC service backed by admin on s.
+func RegisterLevelAdmin(s grpc.ServiceRegistrar, admin *lg.LevelAdmin) {
+	adminpb.RegisterLevelAdminServer(s, &levelAdminServer{admin: admin})
+}
+
+func (s *levelAdminServer) GetLevel(_ context.Context, _ *emptypb.Empty) (*adminpb.LevelReply, error) {
+	return levelReply(s.admin.State()), nil
+}
+
+func (s *levelAdminServer) SetLevel(_ context.Context, req *adminpb.SetLevelRequest) (*adminpb.LevelReply, error) {
+	if req.GetLevel() == "" {
+		return nil, status.Error(codes.InvalidArgument, lg.ErrEmptyLevel.Error())
+	}
+	if req.GetTtl() != nil {
+		if err := req.GetTtl().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %v", err)
+		}
+	}
+
+	state, err := s.admin.SetLevel(req.GetLevel(), req.GetTtl().AsDuration())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return levelReply(state), nil
+}
+
+func levelReply(state lg.LevelState) *adminpb.LevelReply {
+	reply := &adminpb.LevelReply{
+		Level:    state.Level,
+		RevertTo: state.RevertTo,
+	}
+	if !state.ExpiresAt.IsZero() {
+		reply.ExpiresAt = timestamppb.New(state.ExpiresAt)
+	}
+	return reply
+}
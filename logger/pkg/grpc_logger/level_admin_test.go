@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	glg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/grpc_logger"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	adminpb "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/proto/admin"
+)
+
+func newLevelAdminClient(t *testing.T) adminpb.LevelAdminClient {
+	t.Helper()
+	factory, err := slg.NewLoggerFactory(lg.Config{LogLevel: "info", Sinks: []lg.SinkConfig{{Destination: t.TempDir() + "/out.log"}}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = factory.Close() })
+
+	admin := lg.NewLevelAdmin(factory)
+	conn := serve(t, func(s *grpc.Server) { glg.RegisterLevelAdmin(s, admin) }, nil)
+	return adminpb.NewLevelAdminClient(conn)
+}
+
+func TestLevelAdminGRPC(t *testing.T) {
+	client := newLevelAdminClient(t)
+	ctx := context.Background()
+
+	reply, err := client.GetLevel(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, "info", reply.GetLevel())
+	require.Nil(t, reply.GetExpiresAt())
+
+	reply, err = client.SetLevel(ctx, &adminpb.SetLevelRequest{Level: "warn"})
+	require.NoError(t, err)
+	require.Equal(t, "warn", reply.GetLevel())
+	require.Empty(t, reply.GetRevertTo())
+
+	reply, err = client.SetLevel(ctx, &adminpb.SetLevelRequest{Level: "debug", Ttl: durationpb.New(50 * time.Millisecond)})
+	require.NoError(t, err)
+	require.Equal(t, "debug", reply.GetLevel())
+	require.Equal(t, "warn", reply.GetRevertTo())
+	require.NotNil(t, reply.GetExpiresAt())
+
+	require.Eventually(t, func() bool {
+		reply, err := client.GetLevel(ctx, &emptypb.Empty{})
+		return err == nil && reply.GetLevel() == "warn" && reply.GetRevertTo() == ""
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLevelAdminGRPCRejects(t *testing.T) {
+	tests := map[string]*adminpb.SetLevelRequest{
+		"empty request": {},
+		"empty level":   {Ttl: durationpb.New(time.Minute)},
+		"unknown level": {Level: "verbose"},
+		"invalid ttl":   {Level: "debug", Ttl: &durationpb.Duration{Seconds: 1, Nanos: -1}},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := newLevelAdminClient(t)
+
+			_, err := client.SetLevel(context.Background(), req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+			reply, err := client.GetLevel(context.Background(), &emptypb.Empty{})
+			require.NoError(t, err)
+			require.Equal(t, "info", reply.GetLevel())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc/metadata"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const (
+	RequestIDKey = "request_id"
+
+	RequestIDHeader   = "x-request-id"
+	TraceparentHeader = "traceparent"
+)
+
+// WithRequestID stores the request id in the context fields of ctx,
+// the client interceptors send it to the server in RequestIDHeader.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return lg.WithContextFields(ctx, lg.String(RequestIDKey, requestID))
+}
+
+// RequestID returns the request id stored by WithRequestID or read by the server interceptors.
+func RequestID(ctx context.Context) string {
+	for _, field := range lg.FromContext(ctx) {
+		if field.Key == RequestIDKey {
+			if id, ok := field.Value.(string); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// outgoingContext makes sure ctx has a request id and adds it together with
+// the W3C traceparent of ctx to the outgoing metadata.
+func outgoingContext(ctx context.Context) context.Context {
+	requestID := RequestID(ctx)
+	if requestID == "" {
+		requestID = NewRequestID()
+		ctx = WithRequestID(ctx, requestID)
+	}
+
+	pairs := []string{RequestIDHeader, requestID}
+	if traceparent := lg.Traceparent(ctx); traceparent != "" {
+		pairs = append(pairs, TraceparentHeader, traceparent)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// incomingContext reads the request id and the traceparent sent by the client
+// interceptors. A request without id gets a new one.
+func incomingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get(TraceparentHeader); len(values) > 0 {
+		if traceCtx, err := lg.WithTraceparent(ctx, values[0]); err == nil {
+			ctx = traceCtx
+		}
+	}
+
+	requestID := NewRequestID()
+	if values := md.Get(RequestIDHeader); len(values) > 0 && values[0] != "" {
+		requestID = values[0]
+	}
+	return WithRequestID(ctx, requestID)
+}
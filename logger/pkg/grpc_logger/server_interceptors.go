@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const internalErrorMessage = "internal error"
+
+// UnaryServerInterceptor creates a per-RPC logger named after the method,
+// logs the start and the completion of each call with its latency and status
+// code, and turns panics into codes.Internal errors.
+// The logger is available to handlers through LoggerFromContext, and
+// method, peer and request id are added to the context fields of the handler
+// context. The request id and the trace context are read from the metadata
+// set by the client interceptors.
+func UnaryServerInterceptor(factory lg.Factory) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+		ctx = incomingContext(ctx)
+		ctx = lg.WithContextFields(ctx, append([]lg.Field{lg.String(MethodKey, info.FullMethod)}, peerFields(ctx)...)...)
+
+		fields := append(deadlineFields(ctx), sizeFields(RequestSizeKey, req)...)
+		logger := factory.Named(ctx, info.FullMethod, fields...)
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(logger, r)
+			}
+			logCompletion(logger, status.Code(err), err, completionFields(status.Code(err), start)...)
+		}()
+
+		return handler(contextWithLogger(ctx, logger), req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor,
+// it also reports the number of sent and received messages.
+func StreamServerInterceptor(factory lg.Factory) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		ctx := incomingContext(ss.Context())
+		ctx = lg.WithContextFields(ctx, append([]lg.Field{lg.String(MethodKey, info.FullMethod)}, peerFields(ctx)...)...)
+
+		fields := append(deadlineFields(ctx), lg.String(StreamKey, streamType(info.IsClientStream, info.IsServerStream)))
+		logger := factory.Named(ctx, info.FullMethod, fields...)
+
+		stream := &loggingServerStream{ServerStream: ss, ctx: contextWithLogger(ctx, logger)}
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(logger, r)
+			}
+			fields := append(completionFields(status.Code(err), start),
+				lg.Int64(SentKey, stream.sent.Load()),
+				lg.Int64(ReceivedKey, stream.received.Load()))
+			logCompletion(logger, status.Code(err), err, fields...)
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+type loggingServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	sent     atomic.Int64
+	received atomic.Int64
+}
+
+func (s *loggingServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggingServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+	return err
+}
+
+func (s *loggingServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(1)
+	}
+	return err
+}
+
+// recoverPanic logs the stack of the panic as lg.Stack, which takes the place
+// of the stack added to Error records with Config.StacktraceOnError.
+func recoverPanic(logger lg.Logger, r any) error {
+	logger.Error(lg.MsgPanicWasCatched,
+		lg.Any(PanicKey, r),
+		lg.Any(StacktraceKey, lg.CaptureStack(1)))
+	return status.Error(codes.Internal, internalErrorMessage)
+}
+
+func streamType(client, server bool) string {
+	switch {
+	case client && server:
+		return "bidi"
+	case client:
+		return "client"
+	case server:
+		return "server"
+	default:
+		return "unary"
+	}
+}
@@ -0,0 +1,192 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	glg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/grpc_logger"
+)
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+	completesOK = "rpc completes with code OK"
+)
+
+// healthServer answers depending on the service of the request: "panic"
+// panics, "missing" and "down" fail, anything else is serving.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if _, ok := glg.LoggerFromContext(ctx); !ok {
+		return nil, status.Error(codes.Internal, "no logger in context")
+	}
+	switch req.GetService() {
+	case "panic":
+		panic("boom")
+	case "missing":
+		return nil, status.Error(codes.NotFound, "unknown service")
+	case "down":
+		return nil, fmt.Errorf("check: %w", status.Error(codes.Unavailable, "database down"))
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if _, ok := glg.LoggerFromContext(stream.Context()); !ok {
+		return status.Error(codes.Internal, "no logger in context")
+	}
+	for range 3 {
+		if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}); err != nil {
+			return err
+		}
+	}
+	if req.GetService() == "panic" {
+		panic("stream boom")
+	}
+	return nil
+}
+
+// newLoggingServer serves healthServer behind the server interceptors.
+func newLoggingServer(t *testing.T, b backend) (healthpb.HealthClient, func() []map[string]any) {
+	t.Helper()
+	factory, records := newFactory(t, b, lg.Config{LogLevel: "info", StacktraceOnError: true})
+	conn := serve(t, func(s *grpc.Server) { healthpb.RegisterHealthServer(s, healthServer{}) },
+		[]grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(glg.UnaryServerInterceptor(factory)),
+			grpc.ChainStreamInterceptor(glg.StreamServerInterceptor(factory)),
+		})
+	return healthpb.NewHealthClient(conn), func() []map[string]any {
+		t.Helper()
+		require.NoError(t, factory.Close())
+		return records()
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, records := newLoggingServer(t, b)
+			req := &healthpb.HealthCheckRequest{Service: "words"}
+			_, err := client.Check(context.Background(), req)
+			require.NoError(t, err)
+
+			all := records()
+
+			start := findRecord(t, all, lg.MsgStartWithParams)
+			require.Equal(t, float64(proto.Size(req)), start[glg.RequestSizeKey])
+			require.Equal(t, "bufconn", start[glg.PeerKey])
+
+			record := findRecord(t, all, completesOK)
+			require.Equal(t, "info", record[lg.LevelKey])
+			require.Equal(t, checkMethod, record[glg.MethodKey])
+			require.Equal(t, "bufconn", record[glg.PeerKey])
+			require.Equal(t, "OK", record[glg.CodeKey])
+			require.Equal(t, start[glg.RequestIDKey], record[glg.RequestIDKey])
+			require.Contains(t, record, glg.DurationKey)
+			require.NotEmpty(t, record[glg.RequestIDKey])
+			require.NotContains(t, record, lg.ErrorKey)
+		})
+	}
+}
+
+func TestUnaryServerInterceptorErrors(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, records := newLoggingServer(t, b)
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+			require.Equal(t, codes.NotFound, status.Code(err))
+			_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "down"})
+			require.Equal(t, codes.Unavailable, status.Code(err))
+			all := records()
+
+			missing := findRecord(t, all, "rpc completes with code NotFound")
+			require.Equal(t, "warn", missing[lg.LevelKey])
+			require.Equal(t, "rpc error: code = NotFound desc = unknown service", missing[lg.ErrorKey])
+
+			// The error is encoded with its chain and the code is not repeated.
+			down := findRecord(t, all, "rpc completes with code Unavailable")
+			require.Equal(t, "error", down[lg.LevelKey])
+			require.Equal(t, "check: rpc error: code = Unavailable desc = database down", down[lg.ErrorKey])
+			require.Equal(t, "*fmt.wrapError", down[lg.ErrorTypeKey])
+			require.Contains(t, down, lg.ErrorChainKey)
+			require.Equal(t, 1, strings.Count(down[rawKey].(string), `"`+glg.CodeKey+`"`))
+		})
+	}
+}
+
+func TestUnaryServerInterceptorPanic(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, records := newLoggingServer(t, b)
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "panic"})
+			require.Equal(t, codes.Internal, status.Code(err))
+			require.Equal(t, "internal error", status.Convert(err).Message())
+			all := records()
+
+			panicked := findRecord(t, all, lg.MsgPanicWasCatched)
+			require.Equal(t, "error", panicked[lg.LevelKey])
+			require.Equal(t, "boom", panicked[glg.PanicKey])
+			stack, ok := panicked[lg.StacktraceKey].(map[string]any)
+			require.True(t, ok, "stacktrace is not structured: %v", panicked[lg.StacktraceKey])
+			top := stack["0"].(map[string]any)
+			require.True(t, strings.HasSuffix(top["function"].(string), ".healthServer.Check"), top["function"])
+			require.Equal(t, 1, strings.Count(panicked[rawKey].(string), `"`+lg.StacktraceKey+`"`))
+
+			completion := findRecord(t, all, "rpc completes with code Internal")
+			require.Equal(t, "error", completion[lg.LevelKey])
+			require.Equal(t, "Internal", completion[glg.CodeKey])
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			client, records := newLoggingServer(t, b)
+			receive := func(service string) error {
+				stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+				require.NoError(t, err)
+				for {
+					if _, err := stream.Recv(); err != nil {
+						return err
+					}
+				}
+			}
+			require.True(t, errors.Is(receive("words"), io.EOF))
+			require.Equal(t, codes.Internal, status.Code(receive("panic")))
+			all := records()
+
+			start := findRecord(t, all, lg.MsgStartWithParams)
+			require.Equal(t, "server", start[glg.StreamKey])
+
+			record := findRecord(t, all, completesOK)
+			require.Equal(t, watchMethod, record[glg.MethodKey])
+			require.Equal(t, "bufconn", record[glg.PeerKey])
+			require.Equal(t, 3.0, record[glg.SentKey])
+			require.Equal(t, 1.0, record[glg.ReceivedKey])
+			require.NotEmpty(t, record[glg.RequestIDKey])
+
+			panicked := findRecord(t, all, lg.MsgPanicWasCatched)
+			require.Equal(t, "stream boom", panicked[glg.PanicKey])
+			require.IsType(t, map[string]any{}, panicked[lg.StacktraceKey])
+
+			completion := findRecord(t, all, "rpc completes with code Internal")
+			require.Equal(t, 3.0, completion[glg.SentKey])
+		})
+	}
+}
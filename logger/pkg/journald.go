@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DestinationJournald is the destination of sinks sending records to the
+	// systemd journal with its native protocol, configured by
+	// SinkConfig.Journald.
+	DestinationJournald = "journald"
+
+	DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+	journaldMaxField    = 64
+	journaldFieldPrefix = "FIELD_"
+)
+
+// journaldReserved are the fields set by JournaldWriter, fields of records
+// with these names are prefixed with FIELD_.
+var journaldReserved = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+}
+
+// JournaldConfig configures journald sinks. The fields of records become
+// journal fields with upper case names, groups joined by underscores.
+type JournaldConfig struct {
+	// Socket is the journal socket, DefaultJournaldSocket by default.
+	Socket string
+	// Identifier is SYSLOG_IDENTIFIER, Config.ServiceName by default.
+	Identifier string
+}
+
+// JournaldWriter sends records to the systemd journal as datagrams of the
+// native journal protocol. Records too large for a datagram are passed as
+// a sealed memory file on Linux. It is safe for concurrent use.
+type JournaldWriter struct {
+	conn   *net.UnixConn
+	addr   *net.UnixAddr
+	header []byte
+}
+
+var _ RecordWriter = (*JournaldWriter)(nil)
+
+// NewJournaldWriter opens a socket to the journal. The service name is the
+// default SYSLOG_IDENTIFIER and the version the VERSION field.
+func NewJournaldWriter(cfg JournaldConfig, serviceName, version string) (*JournaldWriter, error) {
+	if cfg.Socket == "" {
+		cfg.Socket = DefaultJournaldSocket
+	}
+	if cfg.Identifier == "" {
+		cfg.Identifier = serviceName
+	}
+	if _, err := os.Stat(cfg.Socket); err != nil {
+		return nil, fmt.Errorf("journald socket: %w", err)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("journald socket: %w", err)
+	}
+
+	w := &JournaldWriter{conn: conn, addr: &net.UnixAddr{Name: cfg.Socket, Net: "unixgram"}}
+	if cfg.Identifier != "" {
+		w.header = appendJournaldField(w.header, "SYSLOG_IDENTIFIER", cfg.Identifier)
+	}
+	if version != "" {
+		w.header = appendJournaldField(w.header, "VERSION", version)
+	}
+	return w, nil
+}
+
+// WriteRecord sends r as one datagram.
+func (w *JournaldWriter) WriteRecord(r Record) error {
+	msg := w.AppendMessage(nil, r)
+	_, _, err := w.conn.WriteMsgUnix(msg, nil, w.addr)
+	if err != nil && isMessageTooLarge(err) {
+		err = sendJournaldFile(w.conn, w.addr, msg)
+	}
+	return err
+}
+
+// AppendMessage appends the native protocol message of r.
+func (w *JournaldWriter) AppendMessage(b []byte, r Record) []byte {
+	b = appendJournaldField(b, "MESSAGE", r.Message)
+	b = appendJournaldField(b, "PRIORITY", strconv.Itoa(SyslogSeverity(r.Severity)))
+	b = append(b, w.header...)
+	if frame, ok := r.Frame(); ok {
+		b = appendJournaldField(b, "CODE_FILE", frame.File)
+		b = appendJournaldField(b, "CODE_LINE", strconv.Itoa(frame.Line))
+		b = appendJournaldField(b, "CODE_FUNC", frame.Function)
+	}
+	FlattenFields(r.Attributes, "_", func(key string, value any) {
+		b = appendJournaldField(b, JournaldFieldName(key), FormatValue(value))
+	})
+	return b
+}
+
+// Flush does nothing, messages are sent by WriteRecord.
+func (w *JournaldWriter) Flush() error {
+	return nil
+}
+
+func (w *JournaldWriter) Close() error {
+	return w.conn.Close()
+}
+
+// appendJournaldField appends "NAME=value\n", or for values spanning lines
+// the name, a newline, the little endian 64 bit length and the value.
+func appendJournaldField(b []byte, name, value string) []byte {
+	b = append(b, name...)
+	if !strings.Contains(value, "\n") {
+		b = append(b, '=')
+		b = append(b, value...)
+		return append(b, '\n')
+	}
+	b = append(b, '\n')
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// JournaldFieldName converts key to a journal field name: upper case
+// letters, digits and underscores, not starting with an underscore or a
+// digit and at most 64 characters long.
+func JournaldFieldName(key string) string {
+	name := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			c = '_'
+		}
+		name = append(name, c)
+	}
+
+	trimmed := strings.TrimLeft(string(name), "_")
+	if trimmed == "" || trimmed[0] <= '9' || journaldReserved[trimmed] {
+		trimmed = journaldFieldPrefix + trimmed
+	}
+	if len(trimmed) > journaldMaxField {
+		trimmed = trimmed[:journaldMaxField]
+	}
+	return trimmed
+}
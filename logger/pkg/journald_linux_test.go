@@ -0,0 +1,200 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// parseJournaldMessage decodes the fields of a native protocol message.
+func parseJournaldMessage(t *testing.T, msg []byte) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	for len(msg) > 0 {
+		end := bytes.IndexByte(msg, '\n')
+		require.NotEqual(t, -1, end, "unterminated field")
+		line := string(msg[:end])
+		msg = msg[end+1:]
+
+		if name, value, ok := strings.Cut(line, "="); ok {
+			fields[name] = value
+			continue
+		}
+		require.GreaterOrEqual(t, len(msg), 8)
+		size := int(binary.LittleEndian.Uint64(msg))
+		require.GreaterOrEqual(t, len(msg), 8+size+1)
+		fields[line] = string(msg[8 : 8+size])
+		require.Equal(t, byte('\n'), msg[8+size])
+		msg = msg[8+size+1:]
+	}
+	return fields
+}
+
+// listenJournald starts a journal socket stub and returns its path and the
+// messages it receives. Messages passed as files are read by readFile.
+func listenJournald(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	messages := make(chan []byte, 64)
+	go func() {
+		buf := make([]byte, 1<<20)
+		oob := make([]byte, 1024)
+		for {
+			n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+			if err != nil {
+				return
+			}
+			if oobn > 0 {
+				messages <- readFile(oob[:oobn])
+				continue
+			}
+			messages <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	return path, messages
+}
+
+// receiveFields returns the fields of the first message with the given
+// MESSAGE suffix.
+func receiveFields(t *testing.T, messages <-chan []byte, suffix string) map[string]string {
+	t.Helper()
+	timeout := time.After(receiveTimeout)
+	for {
+		select {
+		case msg := <-messages:
+			fields := parseJournaldMessage(t, msg)
+			if strings.HasSuffix(fields["MESSAGE"], suffix) {
+				return fields
+			}
+		case <-timeout:
+			t.Fatalf("no journal message ending with %q", suffix)
+		}
+	}
+}
+
+func newJournaldFactory(t *testing.T, b backend, socket string) lg.Factory {
+	t.Helper()
+	factory, err := b.newFactory(lg.Config{
+		ServiceName:       "words",
+		Version:           "1.2.3",
+		LogLevel:          "info",
+		StacktraceOnError: true,
+		Sinks: []lg.SinkConfig{{
+			Destination: lg.DestinationJournald,
+			Journald:    &lg.JournaldConfig{Socket: socket},
+		}},
+	})
+	require.NoError(t, err)
+	return factory
+}
+
+func TestJournaldSink(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			socket, messages := listenJournald(t)
+			factory := newJournaldFactory(t, b, socket)
+
+			logger := factory.Named(traceContext(), "worker")
+			logger.Debug("skipped")
+			logger.Info("hello", lg.String("user", "ann"), lg.String("message", "spoofed"),
+				lg.String("_internal", "x"), lg.String("multi", "first\nsecond"),
+				lg.Group("request", lg.String("method", "GET")))
+			logger.Error("failed")
+			require.NoError(t, factory.Close())
+
+			hello := receiveFields(t, messages, "hello")
+			require.Equal(t, "6", hello["PRIORITY"])
+			require.Equal(t, "words", hello["SYSLOG_IDENTIFIER"])
+			require.Equal(t, "1.2.3", hello["VERSION"])
+			require.True(t, strings.HasSuffix(hello["CODE_FILE"], "pkg/journald_linux_test.go"), hello["CODE_FILE"])
+			line, err := strconv.Atoi(hello["CODE_LINE"])
+			require.NoError(t, err)
+			require.NotZero(t, line)
+			require.Contains(t, hello["CODE_FUNC"], "TestJournaldSink")
+			require.Equal(t, traceID.String(), hello["TRACE_ID"])
+			require.Equal(t, spanID.String(), hello["SPAN_ID"])
+			require.Equal(t, "ann", hello["USER"])
+			require.Equal(t, "spoofed", hello["FIELD_MESSAGE"])
+			require.Equal(t, "x", hello["INTERNAL"])
+			require.Equal(t, "first\nsecond", hello["MULTI"])
+			require.Equal(t, "GET", hello["REQUEST_METHOD"])
+
+			failed := receiveFields(t, messages, "failed")
+			require.Equal(t, "3", failed["PRIORITY"])
+			require.Contains(t, failed["STACKTRACE"], "\n")
+		})
+	}
+}
+
+func TestJournaldFieldName(t *testing.T) {
+	tests := map[string]string{
+		"user":                  "USER",
+		"request.method":        "REQUEST_METHOD",
+		"__private":             "PRIVATE",
+		"2fa":                   "FIELD_2FA",
+		"":                      "FIELD_",
+		"priority":              "FIELD_PRIORITY",
+		strings.Repeat("a", 70): strings.Repeat("A", 64),
+	}
+	for key, name := range tests {
+		require.Equal(t, name, lg.JournaldFieldName(key), key)
+	}
+}
+
+func TestJournaldMissingSocket(t *testing.T) {
+	_, err := lg.NewJournaldWriter(lg.JournaldConfig{Socket: filepath.Join(t.TempDir(), "missing")}, "words", "")
+	require.ErrorContains(t, err, "journald socket")
+}
+
+// readFile reads the memory file passed with a message too large for a
+// datagram.
+func readFile(oob []byte) []byte {
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil || len(messages) == 0 {
+		return nil
+	}
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) == 0 {
+		return nil
+	}
+	file := os.NewFile(uintptr(fds[0]), "journal-message")
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil
+	}
+	data, _ := io.ReadAll(io.NewSectionReader(file, 0, info.Size()))
+	return data
+}
+
+func TestJournaldLargeMessage(t *testing.T) {
+	large := strings.Repeat("x", 4<<20)
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			socket, messages := listenJournald(t)
+			factory := newJournaldFactory(t, b, socket)
+
+			factory.Named(traceContext(), "worker").Info("large", lg.String("payload", large))
+			require.NoError(t, factory.Close())
+
+			fields := receiveFields(t, messages, "large")
+			require.Equal(t, large, fields["PAYLOAD"])
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package logger
+
+import (
+	"errors"
+	"net"
+)
+
+func isMessageTooLarge(error) bool {
+	return false
+}
+
+func sendJournaldFile(*net.UnixConn, *net.UnixAddr, []byte) error {
+	return errors.New("journald: message too large")
+}
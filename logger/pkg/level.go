@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrEmptyLevel is returned when a level is changed to an empty one, which
+// would otherwise select the debug default of Config.LogLevel.
+var ErrEmptyLevel = errors.New("empty log level")
+
+// LevelController is implemented by factories whose level can be changed at runtime.
+type LevelController interface {
+	Level() string
+	SetLevel(level string) error
+}
+
+// LevelState describes the current level and a pending revert, if any.
+type LevelState struct {
+	Level     string    `json:"level"`
+	RevertTo  string    `json:"revert_to,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// LevelAdmin changes the level of a LevelController, optionally reverting
+// it to the previous value after a TTL. It serves GET and PUT requests over HTTP.
+type LevelAdmin struct {
+	controller LevelController
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	revertTo  string
+	expiresAt time.Time
+}
+
+func NewLevelAdmin(controller LevelController) *LevelAdmin {
+	return &LevelAdmin{controller: controller}
+}
+
+func (a *LevelAdmin) State() LevelState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.state()
+}
+
+// SetLevel sets the level. If ttl is positive the level returns to its
+// previous value after ttl. Any pending revert is cancelled. An empty level
+// is rejected with ErrEmptyLevel.
+func (a *LevelAdmin) SetLevel(level string, ttl time.Duration) (LevelState, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if level == "" {
+		return a.state(), ErrEmptyLevel
+	}
+
+	previous := a.controller.Level()
+	if a.timer != nil {
+		previous = a.revertTo
+	}
+
+	if err := a.controller.SetLevel(level); err != nil {
+		return a.state(), err
+	}
+
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	a.revertTo = ""
+	a.expiresAt = time.Time{}
+	if ttl > 0 {
+		a.revertTo = previous
+		a.expiresAt = time.Now().Add(ttl)
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() { a.revert(timer) })
+		a.timer = timer
+	}
+
+	return a.state(), nil
+}
+
+func (a *LevelAdmin) revert(timer *time.Timer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.timer != timer {
+		return
+	}
+	_ = a.controller.SetLevel(a.revertTo)
+	a.timer = nil
+	a.revertTo = ""
+	a.expiresAt = time.Time{}
+}
+
+func (a *LevelAdmin) state() LevelState {
+	return LevelState{
+		Level:     a.controller.Level(),
+		RevertTo:  a.revertTo,
+		ExpiresAt: a.expiresAt,
+	}
+}
+
+type setLevelRequest struct {
+	Level string `json:"level"`
+	TTL   string `json:"ttl"`
+}
+
+// ServeHTTP returns the current level on GET and changes it on PUT or POST.
+// The new level and optional ttl (e.g. "5m") are read from the JSON body
+// or from the query parameters.
+func (a *LevelAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeLevelJSON(w, http.StatusOK, a.State())
+	case http.MethodPut, http.MethodPost:
+		req := setLevelRequest{
+			Level: r.URL.Query().Get("level"),
+			TTL:   r.URL.Query().Get("ttl"),
+		}
+		if r.Body != nil && r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeLevelError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		var ttl time.Duration
+		if req.TTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(req.TTL); err != nil {
+				writeLevelError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		state, err := a.SetLevel(req.Level, ttl)
+		if err != nil {
+			writeLevelError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeLevelJSON(w, http.StatusOK, state)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		writeLevelError(w, http.StatusMethodNotAllowed, nil)
+	}
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	msg := http.StatusText(status)
+	if err != nil {
+		msg = err.Error()
+	}
+	writeLevelJSON(w, status, map[string]string{"error": msg})
+}
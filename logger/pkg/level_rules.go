@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const exactRuleBonus = 1 << 16
+
+// LevelRule overrides the level of loggers created in functions matching Pattern.
+type LevelRule struct {
+	Pattern string
+	Level   string
+}
+
+type LevelRules []LevelRule
+
+// ParseLevelRules parses rules in the "pattern=level" form, e.g. "words.Norm=debug"
+// or "main.*=warn". Patterns use path.Match syntax and are matched against the
+// function name without its import path; patterns containing "/" are matched
+// against the full function name.
+func ParseLevelRules(rules []string) (LevelRules, error) {
+	parsed := make(LevelRules, 0, len(rules))
+	for _, rule := range rules {
+		pattern, level, ok := strings.Cut(rule, "=")
+		pattern, level = strings.TrimSpace(pattern), strings.TrimSpace(level)
+		if !ok || pattern == "" || level == "" {
+			return nil, fmt.Errorf("invalid level rule %q, expected pattern=level", rule)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid level rule pattern %q: %w", pattern, err)
+		}
+		parsed = append(parsed, LevelRule{Pattern: pattern, Level: level})
+	}
+	return parsed, nil
+}
+
+// Match returns the index of the most specific rule matching the full function
+// name, or -1. Exact patterns win over wildcard ones, longer patterns win over shorter.
+func (r LevelRules) Match(function string) int {
+	short := function
+	if idx := strings.LastIndex(short, "/"); idx != -1 {
+		short = short[idx+1:]
+	}
+
+	best, bestScore := -1, -1
+	for i, rule := range r {
+		name := short
+		if strings.Contains(rule.Pattern, "/") {
+			name = function
+		}
+		if matched, _ := path.Match(rule.Pattern, name); !matched {
+			continue
+		}
+		score := len(rule.Pattern)
+		if !strings.ContainsAny(rule.Pattern, `*?[\`) {
+			score += exactRuleBonus
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
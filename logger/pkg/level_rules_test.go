@@ -0,0 +1,98 @@
+package logger_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+func TestLevelRulesMatch(t *testing.T) {
+	rules, err := lg.ParseLevelRules([]string{
+		"main.*=warn",
+		" words.Norm = debug ",
+		"words.*=info",
+		"words.(*server).*=error",
+		"example.com/svc/words.*=debug",
+	})
+	require.NoError(t, err)
+	require.Equal(t, lg.LevelRule{Pattern: "words.Norm", Level: "debug"}, rules[1])
+
+	tests := []struct {
+		function string
+		want     int
+	}{
+		{"main.main", 0},
+		{"example.com/cmd/main.run", 0},
+		// Exact patterns win over wildcard ones.
+		{"example.org/words.Norm", 1},
+		{"example.org/words.Split", 2},
+		// Longer patterns win over shorter ones.
+		{"example.org/words.(*server).Get", 3},
+		// Patterns with a slash match the full name.
+		{"example.com/svc/words.Split", 4},
+		{"example.com/svc/words.Norm", 1},
+		{"other.main", -1},
+		{"mainly.run", -1},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.want, rules.Match(tc.function), tc.function)
+	}
+	require.Equal(t, -1, lg.LevelRules(nil).Match("main.main"))
+}
+
+func TestParseLevelRulesInvalid(t *testing.T) {
+	for _, rule := range []string{"main.main", "=debug", "main.*=", "[=debug"} {
+		_, err := lg.ParseLevelRules([]string{rule})
+		require.Error(t, err, rule)
+	}
+}
+
+// logAtLevels logs a record at every level with loggers named after the
+// rules of TestLevelRules.
+func logAtLevels(factory lg.Factory) {
+	for _, name := range []string{"storeQuery", "storeExec", "other"} {
+		logger := factory.Named(context.Background(), name)
+		logger.Debug("debug")
+		logger.Info("info")
+		logger.Warning("warn")
+	}
+}
+
+// Rules override the factory level in both directions, also when it is
+// changed at runtime.
+func TestLevelRules(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			sink, read := fileSink(t, lg.FormatJSON)
+			factory, err := b.newFactory(lg.Config{
+				LogLevel:   "info",
+				LevelRules: []string{"store*=warn", "storeQuery=debug"},
+				Sinks:      []lg.SinkConfig{sink},
+			})
+			require.NoError(t, err)
+
+			logAtLevels(factory)
+			require.NoError(t, factory.(lg.LevelController).SetLevel("error"))
+			logAtLevels(factory)
+			require.NoError(t, factory.(lg.LevelController).SetLevel("debug"))
+			logAtLevels(factory)
+			require.NoError(t, factory.Close())
+
+			var messages []string
+			for _, record := range jsonRecords(t, read()) {
+				messages = append(messages, record[lg.MessageKey].(string))
+			}
+			query := []string{"storeQuery: start", "storeQuery: debug", "storeQuery: info", "storeQuery: warn"}
+			require.Equal(t, slices.Concat(
+				query, []string{"storeExec: warn", "other: start", "other: info", "other: warn"},
+				// At error only the loggers matched by a rule log below error.
+				query, []string{"storeExec: warn"},
+				query, []string{"storeExec: warn", "other: start", "other: debug", "other: info", "other: warn"},
+			), messages)
+		})
+	}
+}
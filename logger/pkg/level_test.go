@@ -0,0 +1,134 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// newLevelAdmin returns a LevelAdmin of a factory of b at level info.
+func newLevelAdmin(t *testing.T, b backend) *lg.LevelAdmin {
+	t.Helper()
+	sink, _ := fileSink(t, lg.FormatJSON)
+	factory, err := b.newFactory(lg.Config{LogLevel: "info", Sinks: []lg.SinkConfig{sink}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = factory.Close() })
+
+	controller, ok := factory.(lg.LevelController)
+	require.True(t, ok)
+	return lg.NewLevelAdmin(controller)
+}
+
+func serveLevel(t *testing.T, admin *lg.LevelAdmin, method, target, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, req)
+
+	reply := map[string]any{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&reply))
+	return rec.Code, reply
+}
+
+func TestLevelAdminHTTP(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			admin := newLevelAdmin(t, b)
+
+			code, reply := serveLevel(t, admin, http.MethodGet, "/level", "")
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, "info", reply["level"])
+
+			code, reply = serveLevel(t, admin, http.MethodPut, "/level?level=warn", "")
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, "warn", reply["level"])
+			require.NotContains(t, reply, "revert_to")
+
+			code, reply = serveLevel(t, admin, http.MethodPost, "/level", `{"level":"error"}`)
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, "error", reply["level"])
+
+			code, reply = serveLevel(t, admin, http.MethodDelete, "/level", "")
+			require.Equal(t, http.StatusMethodNotAllowed, code)
+			require.NotEmpty(t, reply["error"])
+		})
+	}
+}
+
+func TestLevelAdminHTTPRejects(t *testing.T) {
+	tests := []struct {
+		name, target, body string
+	}{
+		{"no level", "/level", ""},
+		{"empty level", "/level?level=", ""},
+		{"empty body level", "/level", `{"level":""}`},
+		{"empty level with ttl", "/level?ttl=5m", ""},
+		{"unknown level", "/level?level=verbose", ""},
+		{"invalid ttl", "/level?level=debug&ttl=soon", ""},
+		{"invalid body", "/level", `{"level":`},
+	}
+	for _, b := range backends {
+		for _, tc := range tests {
+			t.Run(b.name+"/"+tc.name, func(t *testing.T) {
+				admin := newLevelAdmin(t, b)
+
+				code, reply := serveLevel(t, admin, http.MethodPut, tc.target, tc.body)
+				require.Equal(t, http.StatusBadRequest, code)
+				require.NotEmpty(t, reply["error"])
+				require.Equal(t, lg.LevelState{Level: "info"}, admin.State())
+			})
+		}
+	}
+}
+
+func TestLevelAdminTTL(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			admin := newLevelAdmin(t, b)
+
+			state, err := admin.SetLevel("debug", time.Hour)
+			require.NoError(t, err)
+			require.Equal(t, "debug", state.Level)
+			require.Equal(t, "info", state.RevertTo)
+			require.False(t, state.ExpiresAt.IsZero())
+
+			// A second change keeps reverting to the level before the first.
+			code, reply := serveLevel(t, admin, http.MethodPut, "/level", `{"level":"warn","ttl":"50ms"}`)
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, "warn", reply["level"])
+			require.Equal(t, "info", reply["revert_to"])
+			require.Contains(t, reply, "expires_at")
+
+			require.Eventually(t, func() bool {
+				return admin.State() == lg.LevelState{Level: "info"}
+			}, receiveTimeout, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestLevelAdminSetLevelCancelsRevert(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			admin := newLevelAdmin(t, b)
+
+			_, err := admin.SetLevel("debug", 20*time.Millisecond)
+			require.NoError(t, err)
+			state, err := admin.SetLevel("warn", 0)
+			require.NoError(t, err)
+			require.Equal(t, lg.LevelState{Level: "warn"}, state)
+
+			time.Sleep(50 * time.Millisecond)
+			require.Equal(t, lg.LevelState{Level: "warn"}, admin.State())
+
+			_, err = admin.SetLevel("", 0)
+			require.ErrorIs(t, err, lg.ErrEmptyLevel)
+		})
+	}
+}
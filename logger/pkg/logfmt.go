@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AppendLogfmtKey appends key replacing the characters logfmt does not allow
+// in keys (spaces, '=', '"' and control characters) with '_'.
+func AppendLogfmtKey(b []byte, key string) []byte {
+	if key == "" {
+		return append(b, '_')
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		b = utf8.AppendRune(b, r)
+	}
+	return b
+}
+
+// AppendLogfmtValue appends value, quoted when it is empty or contains
+// spaces, '=', '"' or non-printable characters.
+func AppendLogfmtValue(b []byte, value string) []byte {
+	if needsLogfmtQuote(value) {
+		return strconv.AppendQuote(b, value)
+	}
+	return append(b, value...)
+}
+
+func needsLogfmtQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
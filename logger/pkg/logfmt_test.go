@@ -0,0 +1,59 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// textValue is only an encoding.TextMarshaler.
+type textValue string
+
+func (v textValue) MarshalText() ([]byte, error) { return []byte(v), nil }
+
+func TestLogfmtQuoting(t *testing.T) {
+	tests := []struct {
+		name  string
+		field lg.Field
+		want  string
+	}{
+		{"plain", lg.String("v", "plain"), `v=plain`},
+		{"empty", lg.String("v", ""), `v=""`},
+		{"space", lg.String("v", "a b"), `v="a b"`},
+		{"equals", lg.String("v", "a=b"), `v="a=b"`},
+		{"quote", lg.String("v", `say "hi"`), `v="say \"hi\""`},
+		{"backslash", lg.String("v", `C:\logs`), `v=C:\logs`},
+		{"newline", lg.String("v", "a\nb"), `v="a\nb"`},
+		{"tab", lg.String("v", "a\tb"), `v="a\tb"`},
+		{"control", lg.String("v", "a\x00b"), `v="a\x00b"`},
+		{"invalid utf-8", lg.String("v", "a\xffb"), `v="a\xffb"`},
+		{"unicode", lg.String("v", "café"), `v=café`},
+		{"error", lg.Any("v", errors.New("not found")), `v="not found"`},
+		{"text marshaler", lg.Any("v", textValue("a b")), `v="a b"`},
+		{"json", lg.Any("v", map[string]int{"a": 1}), `v="{\"a\":1}"`},
+		{"key with space", lg.String("my key", "v"), `my_key=v`},
+		{"key with equals", lg.String("a=b", "v"), `a_b=v`},
+		{"key with quote", lg.String(`a"b`, "v"), `a_b=v`},
+		{"group key", lg.Group("a b", lg.String("c", "d e")), `a_b.c="d e"`},
+	}
+	for _, b := range backends {
+		for _, tc := range tests {
+			t.Run(b.name+"/"+tc.name, func(t *testing.T) {
+				sink, read := fileSink(t, lg.FormatLogfmt)
+				factory, err := b.newFactory(lg.Config{Sinks: []lg.SinkConfig{sink}})
+				require.NoError(t, err)
+				factory.Named(context.Background(), "worker").Info("quoting", tc.field)
+				require.NoError(t, factory.Close())
+
+				lines := strings.Split(strings.TrimSpace(read()), "\n")
+				require.Len(t, lines, 2)
+				require.True(t, strings.HasSuffix(lines[1], ` message="worker: quoting" `+tc.want), lines[1])
+			})
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	BackendSlog = "slog"
+	BackendZap  = "zap"
+)
+
+const (
+	DurationKey      = "duration"
+	SlowThresholdKey = "slow_threshold"
+	ErrorChainKey    = "error_chain"
+	TableKey         = "table"
+	SQLOperationKey  = "sql.operation"
+)
+
+type Config struct {
+	Backend     string
+	ServiceName string
+	Version     string
+	LogLevel    string
+	OutputPath  string
+
+	// Sinks replace the default outputs, text on stderr and JSON in
+	// OutputPath, see SinkConfig.
+	Sinks []SinkConfig
+
+	// Failover isolates failing sinks from the others, nil writes every
+	// record to all sinks in turn, see FailoverConfig.
+	Failover *FailoverConfig
+
+	// LevelRules override LogLevel for loggers created in matching
+	// functions, e.g. "words.Norm=debug" or "main.*=warn", see ParseLevelRules.
+	// They also override the level set at runtime with SetLevel.
+	LevelRules []string
+
+	// CallerSkip is the number of wrapper frames between the code records are
+	// attributed to and the logger methods, see also WithCallerSkip.
+	CallerSkip int
+
+	// SlowCallThreshold escalates the end record of a logger to Warning when
+	// the time since GetLogger exceeds it. Zero disables the escalation.
+	SlowCallThreshold time.Duration
+
+	// StacktraceOnError adds the caller stack to every Error level record,
+	// stacks are always added to panic records.
+	StacktraceOnError bool
+
+	// ContextExtractors add fields bound to the context passed to GetLogger
+	// to every record, after trace_id and span_id which are always extracted.
+	ContextExtractors []ContextExtractor
+
+	// RedactRules hide sensitive field values in every sink, see
+	// DefaultRedactRules. Values implementing Redactor are always redacted.
+	RedactRules []RedactRule
+
+	// Sampling limits repeated records on hot paths, nil logs everything.
+	Sampling *SamplingConfig
+
+	// Rotation of OutputPath and file sinks, disabled when all values are zero.
+	MaxSizeMB   int
+	MaxAge      time.Duration
+	MaxBackups  int
+	RotateEvery time.Duration
+	Compress    bool
+
+	// ReopenOnSIGHUP reopens the log files on SIGHUP for external logrotate.
+	ReopenOnSIGHUP bool
+
+	// Async moves writing to a background goroutine with a bounded queue,
+	// nil writes synchronously. Queued records are flushed on Close.
+	Async *AsyncConfig
+}
+
+type SQLErrorType int
+
+const (
+	SQLSelect SQLErrorType = iota
+	SQLInsert
+	SQLUpdate
+	SQLDelete
+	SQLBegin
+	SQLCommit
+	SQLRollback
+	// SQLDDL is a schema change: CREATE, ALTER, DROP, TRUNCATE.
+	SQLDDL
+	// SQLMigration is a migration step, its version or name is logged as the table.
+	SQLMigration
+	// SQLUpsert covers MERGE, INSERT ... ON CONFLICT and similar statements.
+	SQLUpsert
+	// SQLConnection is a failure to open or acquire a connection from the pool.
+	SQLConnection
+)
+
+// Logger is the method set shared by the slog and zap backends.
+type Logger interface {
+	WithFields(fields ...Field) Logger
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warning(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	ErrorIn(funcName string, err error, fields ...Field)
+	ErrorSQL(operation SQLErrorType, table string, err error, fields ...Field)
+	ErrorSQLSelect(table string, err error, fields ...Field)
+	ErrorSQLInsert(table string, err error, fields ...Field)
+	ErrorSQLUpdate(table string, err error, fields ...Field)
+	ErrorSQLDelete(table string, err error, fields ...Field)
+	ErrorSQLBegin(err error, fields ...Field)
+	ErrorSQLCommit(err error, fields ...Field)
+	ErrorSQLRollback(err error, fields ...Field)
+	ErrorSQLDDL(table string, err error, fields ...Field)
+	ErrorSQLMigration(migration string, err error, fields ...Field)
+	ErrorSQLUpsert(table string, err error, fields ...Field)
+	ErrorSQLConnection(err error, fields ...Field)
+	End()
+	EndWithError(errPtr *error)
+}
+
+// Factory creates Loggers independently of the backend behind it.
+// Get names the logger after the calling function, Named uses the given
+// name instead, which is useful for middleware like gRPC interceptors.
+type Factory interface {
+	Get(ctx context.Context, fields ...Field) Logger
+	Named(ctx context.Context, name string, fields ...Field) Logger
+	Close() error
+}
+
+var sqlOperationNames = [...]string{
+	SQLSelect:     "select",
+	SQLInsert:     "insert",
+	SQLUpdate:     "update",
+	SQLDelete:     "delete",
+	SQLBegin:      "begin",
+	SQLCommit:     "commit",
+	SQLRollback:   "rollback",
+	SQLDDL:        "ddl",
+	SQLMigration:  "migration",
+	SQLUpsert:     "upsert",
+	SQLConnection: "connection",
+}
+
+func (t SQLErrorType) String() string {
+	if t >= 0 && int(t) < len(sqlOperationNames) {
+		return sqlOperationNames[t]
+	}
+	return "unknown"
+}
+
+// SQLFields returns the operation and, when set, the table of an ErrorSQL record.
+func SQLFields(operation SQLErrorType, table string) []Field {
+	fields := []Field{String(SQLOperationKey, operation.String())}
+	if table != "" {
+		fields = append(fields, String(TableKey, table))
+	}
+	return fields
+}
+
+func SQLErrorMessage(operation SQLErrorType, table string) string {
+	switch operation {
+	case SQLSelect:
+		return fmt.Sprintf(MsgSQLSelectWithError, table)
+	case SQLInsert:
+		return fmt.Sprintf(MsgSQLInsertWithError, table)
+	case SQLUpdate:
+		return fmt.Sprintf(MsgSQLUpdateWithError, table)
+	case SQLDelete:
+		return fmt.Sprintf(MsgSQLDeleteWithError, table)
+	case SQLBegin:
+		return MsgSQLBeginWithError
+	case SQLCommit:
+		return MsgSQLCommitWithError
+	case SQLRollback:
+		return MsgSQLRollbackWithError
+	case SQLDDL:
+		return fmt.Sprintf(MsgSQLDDLWithError, table)
+	case SQLMigration:
+		return fmt.Sprintf(MsgSQLMigrationWithError, table)
+	case SQLUpsert:
+		return fmt.Sprintf(MsgSQLUpsertWithError, table)
+	case SQLConnection:
+		return MsgSQLConnectionWithError
+	default:
+		return fmt.Sprintf(MsgSQLOperationWithError, table)
+	}
+}
@@ -0,0 +1,84 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
+)
+
+const receiveTimeout = 5 * time.Second
+
+var (
+	traceID = trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID  = trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+)
+
+type backend struct {
+	name       string
+	newFactory func(lg.Config) (lg.Factory, error)
+}
+
+var backends = []backend{
+	{"slog", func(cfg lg.Config) (lg.Factory, error) { return slg.NewLoggerFactory(cfg) }},
+	{"zap", func(cfg lg.Config) (lg.Factory, error) { return zlg.NewZapLoggerFactory(cfg) }},
+}
+
+func traceContext() context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+// fileSink returns a sink writing to a file in a temporary directory and
+// the function reading it.
+func fileSink(t *testing.T, format string) (lg.SinkConfig, func() string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.log")
+	return lg.SinkConfig{Destination: path, Format: format}, func() string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+}
+
+// jsonRecords decodes the records of a JSON sink.
+func jsonRecords(t *testing.T, output string) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	return records
+}
+
+// recordMessages returns the messages of records without the logger name.
+func recordMessages(records []map[string]any) []string {
+	var msgs []string
+	for _, record := range records {
+		msg, _ := record[lg.MessageKey].(string)
+		if _, after, ok := strings.Cut(msg, ": "); ok {
+			msg = after
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DestinationOTLP is the destination of sinks exporting records to an
+	// OpenTelemetry collector, configured by SinkConfig.OTLP.
+	DestinationOTLP = "otlp"
+
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http/protobuf"
+
+	defaultOTLPGRPCEndpoint  = "localhost:4317"
+	defaultOTLPHTTPEndpoint  = "localhost:4318"
+	defaultOTLPTimeout       = 10 * time.Second
+	defaultOTLPBatchSize     = 512
+	defaultOTLPFlushInterval = time.Second
+	defaultOTLPQueueSize     = 2048
+	defaultOTLPMaxRetries    = 5
+	defaultOTLPRetryBackoff  = 100 * time.Millisecond
+)
+
+// OTLPConfig configures the export of records to an OpenTelemetry collector.
+// Records are sent in batches of BatchSize (default 512) at least every
+// FlushInterval (default 1s). QueueSize (default 2048) bounds the records
+// waiting for export, new records are dropped when it is full.
+type OTLPConfig struct {
+	// Protocol is OTLPProtocolGRPC (default) or OTLPProtocolHTTP.
+	Protocol string
+	// Endpoint is host:port for gRPC (default localhost:4317). For HTTP it is
+	// a URL or host:port (default localhost:4318), /v1/logs is used when it
+	// has no path.
+	Endpoint string
+	// Insecure disables TLS.
+	Insecure bool
+	// Headers are sent with every request, e.g. for authentication.
+	Headers map[string]string
+	// Timeout bounds every request (default 10s).
+	Timeout time.Duration
+
+	BatchSize     int
+	FlushInterval time.Duration
+	QueueSize     int
+
+	// MaxRetries is the number of retries of a request failing with a
+	// transient error (default 5, negative disables them), waiting
+	// RetryBackoff (default 100ms) doubled after every attempt.
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// WithDefaults returns the config with defaults applied.
+func (c OTLPConfig) WithDefaults() (OTLPConfig, error) {
+	switch c.Protocol {
+	case "", OTLPProtocolGRPC:
+		c.Protocol = OTLPProtocolGRPC
+		if c.Endpoint == "" {
+			c.Endpoint = defaultOTLPGRPCEndpoint
+		}
+	case OTLPProtocolHTTP:
+		if c.Endpoint == "" {
+			c.Endpoint = defaultOTLPHTTPEndpoint
+		}
+	default:
+		return c, fmt.Errorf("unknown OTLP protocol: %s", c.Protocol)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultOTLPTimeout
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultOTLPBatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultOTLPFlushInterval
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaultOTLPQueueSize
+	}
+	switch {
+	case c.MaxRetries == 0:
+		c.MaxRetries = defaultOTLPMaxRetries
+	case c.MaxRetries < 0:
+		c.MaxRetries = 0
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = defaultOTLPRetryBackoff
+	}
+	return c, nil
+}
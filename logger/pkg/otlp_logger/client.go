@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const (
+	httpLogsPath         = "/v1/logs"
+	protobufContentType  = "application/x-protobuf"
+	maxHTTPResponseBytes = 64 << 10
+)
+
+// client sends export requests and returns the number of records rejected
+// by the collector. Errors worth retrying are retryableError.
+type client interface {
+	export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (int64, error)
+	close() error
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+
+func (e retryableError) Unwrap() error { return e.err }
+
+func isRetryable(err error) bool {
+	var retryable retryableError
+	return errors.As(err, &retryable)
+}
+
+func newClient(cfg lg.OTLPConfig) (client, error) {
+	if cfg.Protocol == lg.OTLPProtocolHTTP {
+		return newHTTPClient(cfg)
+	}
+	return newGRPCClient(cfg)
+}
+
+type grpcClient struct {
+	conn    *grpc.ClientConn
+	service collogspb.LogsServiceClient
+	md      metadata.MD
+}
+
+func newGRPCClient(cfg lg.OTLPConfig) (*grpcClient, error) {
+	creds := credentials.NewTLS(&tls.Config{})
+	if cfg.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("otlp grpc client %s: %w", cfg.Endpoint, err)
+	}
+	return &grpcClient{
+		conn:    conn,
+		service: collogspb.NewLogsServiceClient(conn),
+		md:      metadata.New(cfg.Headers),
+	}, nil
+}
+
+func (c *grpcClient) export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (int64, error) {
+	if c.md.Len() > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, c.md)
+	}
+	resp, err := c.service.Export(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted,
+			codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+			return 0, retryableError{err}
+		}
+		return 0, err
+	}
+	return resp.GetPartialSuccess().GetRejectedLogRecords(), nil
+}
+
+func (c *grpcClient) close() error {
+	return c.conn.Close()
+}
+
+type httpClient struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPClient(cfg lg.OTLPConfig) (*httpClient, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https://"
+		if cfg.Insecure {
+			scheme = "http://"
+		}
+		endpoint = scheme + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("otlp http endpoint %s: %w", cfg.Endpoint, err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = httpLogsPath
+	}
+	return &httpClient{url: u.String(), headers: cfg.Headers, client: &http.Client{}}, nil
+}
+
+func (c *httpClient) export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (int64, error) {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", protobufContentType)
+	for key, value := range c.headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return 0, retryableError{err}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseBytes))
+	if err != nil {
+		return 0, retryableError{err}
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		var exportResp collogspb.ExportLogsServiceResponse
+		if proto.Unmarshal(data, &exportResp) != nil {
+			return 0, nil
+		}
+		return exportResp.GetPartialSuccess().GetRejectedLogRecords(), nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+		return 0, retryableError{fmt.Errorf("otlp http export: %s", resp.Status)}
+	default:
+		return 0, fmt.Errorf("otlp http export: %s", resp.Status)
+	}
+}
+
+func (c *httpClient) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
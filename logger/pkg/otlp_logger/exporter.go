@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const (
+	// ScopeName is the instrumentation scope of the exported records.
+	ScopeName = "github.com/guryev-vladislav/digital-showcase/golang/lib/logger"
+
+	maxRetryBackoff = 5 * time.Second
+)
+
+// ExporterStats are the metrics of an Exporter.
+type ExporterStats struct {
+	Endpoint      string
+	QueueDepth    int
+	QueueCapacity int
+	Exported      uint64
+	Dropped       uint64
+	Failed        uint64
+}
+
+type exportItem struct {
+	record  *logspb.LogRecord
+	flushed chan struct{}
+}
+
+var _ lg.RecordWriter = (*Exporter)(nil)
+
+// Exporter sends records to an OpenTelemetry collector over OTLP. Records
+// are queued and sent in batches by a background goroutine, requests
+// failing with transient errors are retried with exponential backoff. It is
+// safe for concurrent use.
+type Exporter struct {
+	cfg      lg.OTLPConfig
+	client   client
+	resource *resourcepb.Resource
+	queue    chan exportItem
+	done     chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	exported atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+// NewExporter returns an exporter of records of the service, its name and
+// version are the service.name and service.version resource attributes.
+func NewExporter(cfg lg.OTLPConfig, serviceName, version string) (*Exporter, error) {
+	cfg, err := cfg.WithDefaults()
+	if err != nil {
+		return nil, err
+	}
+	c, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &resourcepb.Resource{}
+	if serviceName != "" {
+		resource.Attributes = append(resource.Attributes, keyValue(ServiceNameKey, serviceName))
+	}
+	if version != "" {
+		resource.Attributes = append(resource.Attributes, keyValue(ServiceVersionKey, version))
+	}
+
+	e := &Exporter{
+		cfg:      cfg,
+		client:   c,
+		resource: resource,
+		queue:    make(chan exportItem, cfg.QueueSize),
+		done:     make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []*logspb.LogRecord
+	for {
+		select {
+		case item, ok := <-e.queue:
+			if !ok {
+				e.send(batch)
+				return
+			}
+			if item.flushed != nil {
+				e.send(batch)
+				batch = nil
+				close(item.flushed)
+				continue
+			}
+			batch = append(batch, item.record)
+			if len(batch) >= e.cfg.BatchSize {
+				e.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			e.send(batch)
+			batch = nil
+		}
+	}
+}
+
+func (e *Exporter) send(records []*logspb.LogRecord) {
+	if len(records) == 0 {
+		return
+	}
+	req := &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: e.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: ScopeName},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	count := uint64(len(records))
+	backoff := e.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+		rejected, err := e.client.export(ctx, req)
+		cancel()
+		if err == nil {
+			e.exported.Add(count - uint64(rejected))
+			e.failed.Add(uint64(rejected))
+			return
+		}
+		if attempt >= e.cfg.MaxRetries || !isRetryable(err) {
+			e.failed.Add(count)
+			return
+		}
+		time.Sleep(backoff)
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// WriteRecord queues r, it is dropped when the queue is full or the
+// exporter is closed.
+func (e *Exporter) WriteRecord(r lg.Record) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		e.dropped.Add(1)
+		return nil
+	}
+
+	select {
+	case e.queue <- exportItem{record: logRecord(r)}:
+	default:
+		e.dropped.Add(1)
+	}
+	return nil
+}
+
+// Flush waits until the records queued before it are sent.
+func (e *Exporter) Flush() error {
+	e.mu.RLock()
+	if e.closed {
+		e.mu.RUnlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	e.queue <- exportItem{flushed: flushed}
+	e.mu.RUnlock()
+	<-flushed
+	return nil
+}
+
+// Stats returns the metrics of the exporter.
+func (e *Exporter) Stats() ExporterStats {
+	return ExporterStats{
+		Endpoint:      e.cfg.Endpoint,
+		QueueDepth:    len(e.queue),
+		QueueCapacity: cap(e.queue),
+		Exported:      e.exported.Load(),
+		Dropped:       e.dropped.Load(),
+		Failed:        e.failed.Load(),
+	}
+}
+
+// Close sends the queued records and closes the connection.
+func (e *Exporter) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.queue)
+	e.mu.Unlock()
+
+	<-e.done
+	return e.client.close()
+}
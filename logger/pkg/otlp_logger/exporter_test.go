@@ -0,0 +1,323 @@
+package logger_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	olg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/otlp_logger"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+	zlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/zap_logger"
+)
+
+const authHeader = "authorization"
+
+// collector is an in-process OTLP collector stub recording the requests it
+// receives. The first calls fail with the codes of failures.
+type collector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu       sync.Mutex
+	failures []codes.Code
+	calls    int
+	requests []*collogspb.ExportLogsServiceRequest
+	auth     []string
+}
+
+func (c *collector) receive(req *collogspb.ExportLogsServiceRequest, auth string) codes.Code {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if len(c.failures) > 0 {
+		code := c.failures[0]
+		c.failures = c.failures[1:]
+		return code
+	}
+	c.requests = append(c.requests, req)
+	c.auth = append(c.auth, auth)
+	return codes.OK
+}
+
+func (c *collector) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if code := c.receive(req, strings.Join(md.Get(authHeader), ",")); code != codes.OK {
+		return nil, status.Error(code, "stub failure")
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	req := &collogspb.ExportLogsServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch c.receive(req, r.Header.Get(authHeader)) {
+	case codes.OK:
+		resp, _ := proto.Marshal(&collogspb.ExportLogsServiceResponse{})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	case codes.Unavailable:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (c *collector) snapshot() (int, []*collogspb.ExportLogsServiceRequest, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls, c.requests, c.auth
+}
+
+func (c *collector) records() []*logspb.LogRecord {
+	_, requests, _ := c.snapshot()
+	var records []*logspb.LogRecord
+	for _, req := range requests {
+		for _, resourceLogs := range req.ResourceLogs {
+			for _, scopeLogs := range resourceLogs.ScopeLogs {
+				records = append(records, scopeLogs.LogRecords...)
+			}
+		}
+	}
+	return records
+}
+
+// startCollector serves c over protocol and returns its endpoint.
+func startCollector(t *testing.T, c *collector, protocol string) string {
+	t.Helper()
+	if protocol == lg.OTLPProtocolHTTP {
+		server := httptest.NewServer(c)
+		t.Cleanup(server.Close)
+		return server.Listener.Addr().String()
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, c)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func newExporter(t *testing.T, c *collector, protocol string, cfg lg.OTLPConfig) *olg.Exporter {
+	t.Helper()
+	cfg.Protocol = protocol
+	cfg.Endpoint = startCollector(t, c, protocol)
+	cfg.Insecure = true
+	cfg.RetryBackoff = time.Millisecond
+	exporter, err := olg.NewExporter(cfg, "words", "1.2.3")
+	require.NoError(t, err)
+	return exporter
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]*commonpb.AnyValue {
+	values := make(map[string]*commonpb.AnyValue, len(kvs))
+	for _, kv := range kvs {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+var protocols = []string{lg.OTLPProtocolGRPC, lg.OTLPProtocolHTTP}
+
+type backend struct {
+	name       string
+	newFactory func(lg.Config) (lg.Factory, error)
+}
+
+var backends = []backend{
+	{"slog", func(cfg lg.Config) (lg.Factory, error) { return slg.NewLoggerFactory(cfg) }},
+	{"zap", func(cfg lg.Config) (lg.Factory, error) { return zlg.NewZapLoggerFactory(cfg) }},
+}
+
+func TestOTLPSink(t *testing.T) {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID := trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	for _, b := range backends {
+		for _, protocol := range protocols {
+			t.Run(b.name+"/"+protocol, func(t *testing.T) {
+				c := &collector{}
+				factory, err := b.newFactory(lg.Config{
+					ServiceName: "words",
+					Version:     "1.2.3",
+					LogLevel:    "info",
+					Sinks: []lg.SinkConfig{{
+						Destination: lg.DestinationOTLP,
+						OTLP: &lg.OTLPConfig{
+							Protocol: protocol,
+							Endpoint: startCollector(t, c, protocol),
+							Insecure: true,
+							Headers:  map[string]string{authHeader: "Bearer token"},
+						},
+					}},
+				})
+				require.NoError(t, err)
+
+				logger := factory.Named(ctx, "worker")
+				logger.Debug("skipped")
+				logger.Info("hello", lg.String("user", "ann"), lg.Int("count", 3),
+					lg.Group("request", lg.String("method", "GET")))
+				logger.WithFields(lg.Bool("retry", true)).Warning("slow")
+				require.NoError(t, factory.Close())
+
+				_, requests, auth := c.snapshot()
+				require.NotEmpty(t, requests)
+				require.Equal(t, "Bearer token", auth[0])
+				resourceLogs := requests[0].ResourceLogs[0]
+				resource := attributes(resourceLogs.Resource.Attributes)
+				require.Equal(t, "words", resource[olg.ServiceNameKey].GetStringValue())
+				require.Equal(t, "1.2.3", resource[olg.ServiceVersionKey].GetStringValue())
+				require.Equal(t, olg.ScopeName, resourceLogs.ScopeLogs[0].Scope.Name)
+
+				byMessage := map[string]*logspb.LogRecord{}
+				for _, record := range c.records() {
+					body := record.Body.GetStringValue()
+					byMessage[body[strings.LastIndex(body, " ")+1:]] = record
+				}
+				require.NotContains(t, byMessage, "skipped")
+
+				hello := byMessage["hello"]
+				require.NotNil(t, hello)
+				require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, hello.SeverityNumber)
+				require.Equal(t, "INFO", hello.SeverityText)
+				require.NotZero(t, hello.TimeUnixNano)
+				require.Equal(t, traceID[:], hello.TraceId)
+				require.Equal(t, spanID[:], hello.SpanId)
+
+				attrs := attributes(hello.Attributes)
+				require.NotContains(t, attrs, lg.TraceIDKey)
+				require.NotContains(t, attrs, lg.SpanIDKey)
+				require.Equal(t, "ann", attrs["user"].GetStringValue())
+				require.Equal(t, int64(3), attrs["count"].GetIntValue())
+				request := attributes(attrs["request"].GetKvlistValue().GetValues())
+				require.Equal(t, "GET", request["method"].GetStringValue())
+				require.True(t, strings.HasSuffix(attrs[olg.CodeFilepathKey].GetStringValue(), "otlp_logger/exporter_test.go"))
+				require.NotZero(t, attrs[olg.CodeLinenoKey].GetIntValue())
+
+				slow := byMessage["slow"]
+				require.NotNil(t, slow)
+				require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, slow.SeverityNumber)
+				require.Equal(t, "WARN", slow.SeverityText)
+				require.True(t, attributes(slow.Attributes)["retry"].GetBoolValue())
+			})
+		}
+	}
+}
+
+func TestExporterBatching(t *testing.T) {
+	for _, protocol := range protocols {
+		t.Run(protocol, func(t *testing.T) {
+			c := &collector{}
+			exporter := newExporter(t, c, protocol, lg.OTLPConfig{BatchSize: 4, FlushInterval: time.Hour})
+			for range 10 {
+				require.NoError(t, exporter.WriteRecord(lg.Record{Time: time.Now(), Severity: lg.SeverityInfo, Message: "record"}))
+			}
+			require.NoError(t, exporter.Close())
+
+			_, requests, _ := c.snapshot()
+			var sizes []int
+			for _, req := range requests {
+				sizes = append(sizes, len(req.ResourceLogs[0].ScopeLogs[0].LogRecords))
+			}
+			require.Equal(t, []int{4, 4, 2}, sizes)
+			require.Equal(t, uint64(10), exporter.Stats().Exported)
+		})
+	}
+}
+
+func TestExporterFlush(t *testing.T) {
+	c := &collector{}
+	exporter := newExporter(t, c, lg.OTLPProtocolGRPC, lg.OTLPConfig{FlushInterval: time.Hour})
+	t.Cleanup(func() { require.NoError(t, exporter.Close()) })
+
+	require.NoError(t, exporter.WriteRecord(lg.Record{Time: time.Now(), Severity: lg.SeverityInfo, Message: "record"}))
+	require.NoError(t, exporter.Flush())
+	require.Len(t, c.records(), 1)
+}
+
+func TestExporterRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		failures   []codes.Code
+		calls      int
+		exported   uint64
+		failed     uint64
+	}{
+		{"transient errors", 0, []codes.Code{codes.Unavailable, codes.Unavailable}, 3, 1, 0},
+		{"retries exhausted", 1, []codes.Code{codes.Unavailable, codes.Unavailable}, 2, 0, 1},
+		{"retries disabled", -1, []codes.Code{codes.Unavailable}, 1, 0, 1},
+		{"permanent error", 0, []codes.Code{codes.InvalidArgument}, 1, 0, 1},
+	}
+	for _, protocol := range protocols {
+		for _, tc := range tests {
+			t.Run(protocol+"/"+tc.name, func(t *testing.T) {
+				c := &collector{failures: tc.failures}
+				exporter := newExporter(t, c, protocol, lg.OTLPConfig{MaxRetries: tc.maxRetries})
+				require.NoError(t, exporter.WriteRecord(lg.Record{Time: time.Now(), Severity: lg.SeverityError, Message: "record"}))
+				require.NoError(t, exporter.Close())
+
+				calls, _, _ := c.snapshot()
+				require.Equal(t, tc.calls, calls)
+				require.Len(t, c.records(), int(tc.exported))
+				stats := exporter.Stats()
+				require.Equal(t, tc.exported, stats.Exported)
+				require.Equal(t, tc.failed, stats.Failed)
+			})
+		}
+	}
+}
+
+func TestExporterDropsAfterClose(t *testing.T) {
+	c := &collector{}
+	exporter := newExporter(t, c, lg.OTLPProtocolGRPC, lg.OTLPConfig{})
+	require.NoError(t, exporter.Close())
+
+	require.NoError(t, exporter.WriteRecord(lg.Record{Time: time.Now(), Severity: lg.SeverityInfo, Message: "late"}))
+	require.Equal(t, uint64(1), exporter.Stats().Dropped)
+	require.Empty(t, c.records())
+}
+
+func TestUnknownOTLPProtocol(t *testing.T) {
+	_, err := slg.NewLoggerFactory(lg.Config{Sinks: []lg.SinkConfig{{
+		Destination: lg.DestinationOTLP,
+		OTLP:        &lg.OTLPConfig{Protocol: "http/json"},
+	}}})
+	require.ErrorContains(t, err, "unknown OTLP protocol")
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// Attribute keys of the OpenTelemetry semantic conventions.
+const (
+	ServiceNameKey    = "service.name"
+	ServiceVersionKey = "service.version"
+	CodeFunctionKey   = "code.function"
+	CodeFilepathKey   = "code.filepath"
+	CodeLinenoKey     = "code.lineno"
+)
+
+// logRecord converts r, its trace_id and span_id attributes set the trace
+// context of the exported record.
+func logRecord(r lg.Record) *logspb.LogRecord {
+	record := &logspb.LogRecord{
+		TimeUnixNano:         uint64(r.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+		SeverityNumber:       logspb.SeverityNumber(r.Severity),
+		SeverityText:         r.Level,
+		Body:                 stringValue(r.Message),
+		Attributes:           make([]*commonpb.KeyValue, 0, len(r.Attributes)+3),
+	}
+	if r.Time.IsZero() {
+		record.TimeUnixNano = 0
+	}
+
+	if frame, ok := r.Frame(); ok {
+		record.Attributes = append(record.Attributes,
+			keyValue(CodeFunctionKey, frame.Function),
+			keyValue(CodeFilepathKey, frame.File),
+			keyValue(CodeLinenoKey, frame.Line),
+		)
+	}
+
+	for _, field := range r.Attributes {
+		switch field.Key {
+		case lg.TraceIDKey:
+			if id, err := trace.TraceIDFromHex(fmt.Sprint(field.Value)); err == nil {
+				record.TraceId = id[:]
+				continue
+			}
+		case lg.SpanIDKey:
+			if id, err := trace.SpanIDFromHex(fmt.Sprint(field.Value)); err == nil {
+				record.SpanId = id[:]
+				continue
+			}
+		}
+		record.Attributes = append(record.Attributes, keyValue(field.Key, field.Value))
+	}
+	return record
+}
+
+func keyValue(key string, value any) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: anyValue(value)}
+}
+
+func stringValue(s string) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+}
+
+func intValue(i int64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+}
+
+func doubleValue(f float64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+}
+
+// anyValue converts field values to OTLP values: groups and maps become
+// key-value lists, slices arrays, durations milliseconds like in JSON
+// records and other values strings.
+func anyValue(value any) *commonpb.AnyValue {
+	switch v := value.(type) {
+	case nil:
+		return &commonpb.AnyValue{}
+	case string:
+		return stringValue(v)
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return intValue(int64(v))
+	case int8:
+		return intValue(int64(v))
+	case int16:
+		return intValue(int64(v))
+	case int32:
+		return intValue(int64(v))
+	case int64:
+		return intValue(v)
+	case uint:
+		return intValue(int64(v))
+	case uint8:
+		return intValue(int64(v))
+	case uint16:
+		return intValue(int64(v))
+	case uint32:
+		return intValue(int64(v))
+	case uint64:
+		return intValue(int64(v))
+	case uintptr:
+		return intValue(int64(v))
+	case float32:
+		return doubleValue(float64(v))
+	case float64:
+		return doubleValue(v)
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v}}
+	case time.Duration:
+		return doubleValue(float64(v) / float64(time.Millisecond))
+	case time.Time:
+		return stringValue(v.Format(lg.TimeLayout))
+	case lg.Stack:
+		return stringValue(v.String())
+	case lg.GroupValue:
+		values := make([]*commonpb.KeyValue, 0, len(v))
+		for _, field := range v {
+			values = append(values, keyValue(field.Key, field.Value))
+		}
+		return kvlistValue(values)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]*commonpb.KeyValue, 0, len(v))
+		for _, key := range keys {
+			values = append(values, keyValue(key, v[key]))
+		}
+		return kvlistValue(values)
+	case error:
+		return stringValue(v.Error())
+	case fmt.Stringer:
+		return stringValue(v.String())
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return stringValue(string(text))
+		}
+	}
+
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		values := make([]*commonpb.AnyValue, rv.Len())
+		for i := range values {
+			values[i] = anyValue(rv.Index(i).Interface())
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{
+			ArrayValue: &commonpb.ArrayValue{Values: values},
+		}}
+	}
+	if b, err := json.Marshal(value); err == nil {
+		return stringValue(string(b))
+	}
+	return stringValue(fmt.Sprint(value))
+}
+
+func kvlistValue(values []*commonpb.KeyValue) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{
+		KvlistValue: &commonpb.KeyValueList{Values: values},
+	}}
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Outputs owns the writers of a factory: rotating files to reopen and async
+// writers to flush on Close. Config.Async wraps every writer it returns.
+type Outputs struct {
+	cfg     Config
+	writers map[string]io.Writer
+	files   []*RotatingFile
+	queue   []*AsyncWriter
+	closers []io.Closer
+}
+
+func NewOutputs(cfg Config) *Outputs {
+	return &Outputs{cfg: cfg, writers: make(map[string]io.Writer)}
+}
+
+// Open returns the writer of a sink destination: stderr, stdout or a file
+// rotated with the settings of the factory Config. Sinks sharing a
+// destination share its writer.
+func (o *Outputs) Open(destination string) (io.Writer, error) {
+	if w, ok := o.writers[destination]; ok {
+		return w, nil
+	}
+
+	var w io.Writer
+	switch destination {
+	case DestinationStderr:
+		w = os.Stderr
+	case DestinationStdout:
+		w = os.Stdout
+	default:
+		cfg := o.cfg
+		cfg.OutputPath = destination
+		file, err := OpenRotatingFile(cfg)
+		if err != nil {
+			return nil, err
+		}
+		o.files = append(o.files, file)
+		w = file
+	}
+
+	w, err := o.wrap(destination, w)
+	if err != nil {
+		return nil, err
+	}
+	o.writers[destination] = w
+	return w, nil
+}
+
+func (o *Outputs) wrap(name string, w io.Writer) (io.Writer, error) {
+	if o.cfg.Async == nil {
+		return w, nil
+	}
+	a, err := NewAsyncWriter(name, w, *o.cfg.Async)
+	if err != nil {
+		return nil, err
+	}
+	o.queue = append(o.queue, a)
+	return a, nil
+}
+
+type recordWriteCloser interface {
+	RecordWriter
+	io.Closer
+}
+
+// OpenRecordWriter returns the writer of a syslog or journald sink, it is
+// closed with the outputs.
+func (o *Outputs) OpenRecordWriter(sink SinkConfig) (RecordWriter, error) {
+	var (
+		w   recordWriteCloser
+		err error
+	)
+	switch sink.Destination {
+	case DestinationSyslog:
+		w, err = NewSyslogWriter(*sink.Syslog, o.cfg.ServiceName, o.cfg.Version)
+	case DestinationJournald:
+		w, err = NewJournaldWriter(*sink.Journald, o.cfg.ServiceName, o.cfg.Version)
+	default:
+		return nil, fmt.Errorf("no record writer for destination %s", sink.Destination)
+	}
+	if err != nil {
+		return nil, err
+	}
+	o.Track(w)
+	return w, nil
+}
+
+// Colored reports whether the text format of sink colors the levels, see
+// SinkConfig.Color.
+func (o *Outputs) Colored(sink SinkConfig) bool {
+	switch sink.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return o.IsTerminal(sink.Destination)
+	}
+}
+
+// IsTerminal reports whether destination is stderr or stdout attached to a
+// terminal, see IsTerminal.
+func (o *Outputs) IsTerminal(destination string) bool {
+	switch destination {
+	case DestinationStderr:
+		return IsTerminal(os.Stderr)
+	case DestinationStdout:
+		return IsTerminal(os.Stdout)
+	default:
+		return false
+	}
+}
+
+// HasFiles reports whether any file was opened.
+func (o *Outputs) HasFiles() bool {
+	return len(o.files) > 0
+}
+
+// Reopen reopens every file, see RotatingFile.Reopen.
+func (o *Outputs) Reopen() error {
+	var errs []error
+	for _, file := range o.files {
+		errs = append(errs, file.Reopen())
+	}
+	return errors.Join(errs...)
+}
+
+// AsyncStats returns the metrics of the async writers.
+func (o *Outputs) AsyncStats() []AsyncStats {
+	stats := make([]AsyncStats, 0, len(o.queue))
+	for _, a := range o.queue {
+		stats = append(stats, a.Stats())
+	}
+	return stats
+}
+
+// Track closes c with the outputs, e.g. the exporters of OTLP sinks.
+func (o *Outputs) Track(c io.Closer) {
+	o.closers = append(o.closers, c)
+}
+
+// Close flushes the async writers, closes the tracked closers and the files.
+func (o *Outputs) Close() error {
+	var errs []error
+	for _, c := range o.closers {
+		errs = append(errs, c.Close())
+	}
+	for _, a := range o.queue {
+		errs = append(errs, a.Close())
+	}
+	for _, file := range o.files {
+		errs = append(errs, file.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Severity numbers of the OpenTelemetry log data model, the scale of
+// Record.Severity.
+const (
+	SeverityDebug = 5
+	SeverityInfo  = 9
+	SeverityWarn  = 13
+	SeverityError = 17
+	SeverityFatal = 21
+)
+
+// Record is a log record in backend-neutral form. The record handler of the
+// slog backend and the record core of the zap backend pass them to the
+// sinks sending structured records: OTLP, syslog and journald.
+type Record struct {
+	Time       time.Time
+	Severity   int32
+	Level      string
+	Message    string
+	PC         uintptr
+	Attributes []Field
+}
+
+// RecordWriter receives the records of a structured sink.
+type RecordWriter interface {
+	WriteRecord(r Record) error
+	// Flush waits until the records written before it are delivered.
+	Flush() error
+}
+
+// Frame returns the caller of the record, if any.
+func (r Record) Frame() (runtime.Frame, bool) {
+	if r.PC == 0 {
+		return runtime.Frame{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	return frame, true
+}
+
+// FlattenFields calls fn with every field, the fields of groups and the
+// entries of maps are passed with their keys joined by sep.
+func FlattenFields(fields []Field, sep string, fn func(key string, value any)) {
+	for _, field := range fields {
+		flattenValue(field.Key, field.Value, sep, fn)
+	}
+}
+
+func flattenValue(key string, value any, sep string, fn func(key string, value any)) {
+	switch v := value.(type) {
+	case GroupValue:
+		for _, field := range v {
+			flattenValue(joinKey(key, field.Key, sep), field.Value, sep, fn)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flattenValue(joinKey(key, k, sep), v[k], sep, fn)
+		}
+	default:
+		fn(key, value)
+	}
+}
+
+func joinKey(prefix, key, sep string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + sep + key
+}
+
+// FormatValue formats field values for text destinations: durations and
+// times like the logfmt format, other values without a text form as JSON.
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(TimeLayout)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	if b, err := json.Marshal(value); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
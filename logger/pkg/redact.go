@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	RedactMask = "mask"
+	RedactHash = "hash"
+
+	redactedMask   = "***"
+	redactedPrefix = "sha256:"
+	hashLength     = 16
+)
+
+// RedactRule hides sensitive values of fields. A rule either matches field
+// keys, redacting the whole value including nested groups, or matches parts
+// of string values with a regular expression.
+type RedactRule struct {
+	// Key is a path.Match pattern matched case-insensitively against field
+	// keys at any nesting level, e.g. "*password*".
+	Key string
+	// Value is a regular expression, its matches in string values are redacted.
+	// Errors, fmt.Stringer and other values logged with Any are matched in
+	// their rendered form, which replaces them when a match is redacted.
+	Value string
+	// Strategy is RedactMask (default), replacing the value with "***", or
+	// RedactHash, replacing it with a short SHA-256 to keep values comparable.
+	Strategy string
+}
+
+// DefaultRedactRules mask common credentials and hash email addresses.
+var DefaultRedactRules = []RedactRule{
+	{Key: "*password*"},
+	{Key: "*passwd*"},
+	{Key: "*secret*"},
+	{Key: "*token*"},
+	{Key: "*api_key*"},
+	{Key: "*apikey*"},
+	{Key: "authorization"},
+	{Key: "cookie"},
+	{Value: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Strategy: RedactHash},
+}
+
+// Redactor is implemented by types that hide their sensitive parts, the
+// result of Redact is logged instead of the value.
+type Redactor interface {
+	Redact() any
+}
+
+type keyRule struct {
+	pattern  string
+	strategy string
+}
+
+type valueRule struct {
+	re       *regexp.Regexp
+	strategy string
+}
+
+// Redaction applies redact rules to fields, it is used by both backends.
+// The zero value only applies Redactor.
+type Redaction struct {
+	keys   []keyRule
+	values []valueRule
+}
+
+// NewRedaction validates and compiles rules.
+func NewRedaction(rules []RedactRule) (*Redaction, error) {
+	r := &Redaction{}
+	for _, rule := range rules {
+		strategy := strings.ToLower(rule.Strategy)
+		switch strategy {
+		case "":
+			strategy = RedactMask
+		case RedactMask, RedactHash:
+		default:
+			return nil, fmt.Errorf("unknown redact strategy %q", rule.Strategy)
+		}
+
+		switch {
+		case rule.Key != "" && rule.Value != "":
+			return nil, fmt.Errorf("redact rule %q must set either Key or Value", rule.Key)
+		case rule.Key != "":
+			pattern := strings.ToLower(rule.Key)
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid redact key pattern %q: %w", rule.Key, err)
+			}
+			r.keys = append(r.keys, keyRule{pattern: pattern, strategy: strategy})
+		case rule.Value != "":
+			re, err := regexp.Compile(rule.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redact value pattern %q: %w", rule.Value, err)
+			}
+			r.values = append(r.values, valueRule{re: re, strategy: strategy})
+		default:
+			return nil, fmt.Errorf("redact rule must set Key or Value")
+		}
+	}
+	return r, nil
+}
+
+// MatchKey returns the strategy of the first key rule matching key.
+func (r *Redaction) MatchKey(key string) (string, bool) {
+	if len(r.keys) == 0 {
+		return "", false
+	}
+	key = strings.ToLower(key)
+	for _, rule := range r.keys {
+		if matched, _ := path.Match(rule.pattern, key); matched {
+			return rule.strategy, true
+		}
+	}
+	return "", false
+}
+
+// String redacts the matches of the value rules in s.
+func (r *Redaction) String(s string) string {
+	for _, rule := range r.values {
+		s = rule.re.ReplaceAllStringFunc(s, func(match string) string {
+			return Conceal(rule.strategy, match)
+		})
+	}
+	return s
+}
+
+// Render redacts the matches of the value rules in the rendering of an error,
+// a fmt.Stringer or another value logged with Any. It returns false when
+// nothing matched, the value is then logged as is.
+func (r *Redaction) Render(value any) (string, bool) {
+	if len(r.values) == 0 || value == nil {
+		return "", false
+	}
+	rendered := fmt.Sprint(value)
+	redacted := r.String(rendered)
+	return redacted, redacted != rendered
+}
+
+// Field redacts field and the fields of nested groups. Stack traces are
+// kept as is.
+func (r *Redaction) Field(field Field) Field {
+	if field.Key == StacktraceKey {
+		return field
+	}
+	if redactor, ok := field.Value.(Redactor); ok {
+		field.Value = redactor.Redact()
+	}
+	if strategy, ok := r.MatchKey(field.Key); ok {
+		field.Value = Conceal(strategy, fmt.Sprint(field.Value))
+		return field
+	}
+
+	switch value := field.Value.(type) {
+	case GroupValue:
+		field.Value = GroupValue(r.Fields(value))
+	case string:
+		field.Value = r.String(value)
+	case bool, int, int64, float64, time.Duration, time.Time:
+	default:
+		if redacted, ok := r.Render(value); ok {
+			field.Value = redacted
+		}
+	}
+	return field
+}
+
+func (r *Redaction) Fields(fields []Field) []Field {
+	redacted := make([]Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = r.Field(field)
+	}
+	return redacted
+}
+
+// Conceal replaces value according to strategy.
+func Conceal(strategy, value string) string {
+	if strategy == RedactHash {
+		sum := sha256.Sum256([]byte(value))
+		return redactedPrefix + hex.EncodeToString(sum[:])[:hashLength]
+	}
+	return redactedMask
+}
@@ -0,0 +1,131 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const (
+	email = "jane@example.com"
+	ssn   = "123-45-6789"
+)
+
+var redactRules = append(lg.DefaultRedactRules,
+	lg.RedactRule{Key: "card_number", Strategy: lg.RedactHash},
+	lg.RedactRule{Value: `\b\d{3}-\d{2}-\d{4}\b`},
+)
+
+type account struct {
+	Name  string
+	Email string
+}
+
+type ssnStringer string
+
+func (s ssnStringer) String() string { return "ssn " + string(s) }
+
+type card string
+
+func (c card) Redact() any { return "****" + string(c[len(c)-4:]) }
+
+// redactedRecord logs fields through a factory of b with redactRules and
+// returns the record.
+func redactedRecord(t *testing.T, b backend, log func(lg.Logger)) map[string]any {
+	t.Helper()
+	sink, read := fileSink(t, lg.FormatJSON)
+	factory, err := b.newFactory(lg.Config{Sinks: []lg.SinkConfig{sink}, RedactRules: redactRules})
+	require.NoError(t, err)
+	log(factory.Named(context.Background(), "worker"))
+	require.NoError(t, factory.Close())
+
+	records := jsonRecords(t, read())
+	require.NotEmpty(t, records)
+	return records[len(records)-1]
+}
+
+func TestRedactKeys(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			record := redactedRecord(t, b, func(logger lg.Logger) {
+				logger.WithFields(lg.String("api_token", "abc")).Info("keys",
+					lg.String("password", "hunter2"),
+					lg.String("Authorization", "Bearer abc"),
+					lg.Int("card_number", 4111),
+					lg.Any("db_secret", errors.New("s3cr3t")),
+					lg.Group("client_secret", lg.String("id", "app")),
+					lg.String("user", "jane"))
+			})
+
+			require.Equal(t, "***", record["api_token"])
+			require.Equal(t, "***", record["password"])
+			require.Equal(t, "***", record["Authorization"])
+			require.Equal(t, lg.Conceal(lg.RedactHash, "4111"), record["card_number"])
+			require.Equal(t, "***", record["db_secret"])
+			require.Equal(t, "***", record["client_secret"])
+			require.Equal(t, "jane", record["user"])
+		})
+	}
+}
+
+func TestRedactValues(t *testing.T) {
+	hashed := lg.Conceal(lg.RedactHash, email)
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			record := redactedRecord(t, b, func(logger lg.Logger) {
+				logger.Info("values",
+					lg.String("contact", "mail "+email+" today"),
+					lg.Err(errors.New("user "+email+" not found")),
+					lg.Any("cause", errors.New("plain failure")),
+					lg.Any("id", ssnStringer(ssn)),
+					lg.Any("account", account{Name: "jane", Email: email}),
+					lg.Any("emails", []string{email}),
+					lg.Any("card", card("4111111111111111")),
+					lg.Int("count", 3))
+			})
+
+			require.Equal(t, "mail "+hashed+" today", record["contact"])
+			require.Equal(t, "user "+hashed+" not found", record["error"])
+			require.Equal(t, "plain failure", record["cause"])
+			require.Equal(t, "ssn ***", record["id"])
+			require.Equal(t, "{jane "+hashed+"}", record["account"])
+			require.Equal(t, "["+hashed+"]", record["emails"])
+			require.Equal(t, "****1111", record["card"])
+			require.Equal(t, 3.0, record["count"])
+		})
+	}
+}
+
+func TestRedactGroups(t *testing.T) {
+	hashed := lg.Conceal(lg.RedactHash, email)
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			record := redactedRecord(t, b, func(logger lg.Logger) {
+				logger.WithFields(lg.Group("session", lg.String("cookie", "sid=1"), lg.String("owner", email))).
+					Info("groups", lg.Group("request",
+						lg.String("password", "hunter2"),
+						lg.Group("user",
+							lg.String("email", email),
+							lg.Any("lookup", errors.New("no record for "+ssn)),
+							lg.Int("age", 42))))
+			})
+
+			session, ok := record["session"].(map[string]any)
+			require.True(t, ok, record)
+			require.Equal(t, map[string]any{"cookie": "***", "owner": hashed}, session)
+
+			request, ok := record["request"].(map[string]any)
+			require.True(t, ok, record)
+			require.Equal(t, "***", request["password"])
+			require.Equal(t, map[string]any{
+				"email":  hashed,
+				"lookup": "no record for ***",
+				"age":    42.0,
+			}, request["user"])
+		})
+	}
+}
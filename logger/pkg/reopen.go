@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reopener is implemented by factories and writers that can reopen their log file.
+type Reopener interface {
+	Reopen() error
+}
+
+// ReopenOnSignal calls r.Reopen each time one of sigs is received, SIGHUP by default.
+// Errors are passed to onError when it is not nil. The returned function stops watching.
+func ReopenOnSignal(r Reopener, onError func(error), sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := r.Reopen(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
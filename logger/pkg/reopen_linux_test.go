@@ -0,0 +1,95 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+type countingReopener struct {
+	calls atomic.Int32
+}
+
+func (r *countingReopener) Reopen() error {
+	r.calls.Add(1)
+	return nil
+}
+
+// sighup sends SIGHUP to the test process and waits for its delivery. The
+// test keeps receiving SIGHUP so that it does not terminate the process
+// once the watchers are stopped.
+func sighup(t *testing.T) {
+	t.Helper()
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, syscall.SIGHUP)
+	defer signal.Stop(received)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	select {
+	case <-received:
+	case <-time.After(receiveTimeout):
+		require.FailNow(t, "SIGHUP not received")
+	}
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	r := &countingReopener{}
+	stop := lg.ReopenOnSignal(r, nil)
+
+	sighup(t)
+	require.Eventually(t, func() bool { return r.calls.Load() == 1 }, receiveTimeout, time.Millisecond)
+
+	stop()
+	stop()
+	sighup(t)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), r.calls.Load())
+}
+
+func TestReopenOnSIGHUP(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			moved := filepath.Join(dir, "app.log.1")
+			// Records failing after Close show that the watcher still
+			// reopens the files and logs the failure.
+			var failures atomic.Int32
+			factory, err := b.newFactory(lg.Config{
+				Sinks:          []lg.SinkConfig{{Destination: path}},
+				ReopenOnSIGHUP: true,
+				Failover: &lg.FailoverConfig{
+					OnError: func(string, error) { failures.Add(1) },
+				},
+			})
+			require.NoError(t, err)
+
+			logger := factory.Named(context.Background(), "worker")
+			logger.Info("before")
+			require.NoError(t, os.Rename(path, moved))
+			sighup(t)
+			require.Eventually(t, func() bool {
+				_, err := os.Stat(path)
+				return err == nil
+			}, receiveTimeout, time.Millisecond)
+			logger.Info("after")
+
+			require.NoError(t, factory.Close())
+			require.Equal(t, []string{"start", "before"}, recordMessages(jsonRecords(t, readLog(t, moved))))
+			require.Equal(t, []string{"after"}, recordMessages(jsonRecords(t, readLog(t, path))))
+
+			closed := failures.Load()
+			sighup(t)
+			time.Sleep(50 * time.Millisecond)
+			require.Equal(t, closed, failures.Load())
+		})
+	}
+}
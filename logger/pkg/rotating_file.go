@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	megabyte         = 1024 * 1024
+)
+
+// RotatingFile is an io.Writer that appends to Config.OutputPath and rotates
+// the file by size and by time. Rotated files are renamed with a UTC timestamp
+// suffix, optionally gzipped and removed according to MaxBackups and MaxAge.
+// A failed rotation keeps writing to the current file and is retried by the
+// next write; Write does not return its error but reports it on stderr when
+// rotations start failing. It is safe for concurrent use.
+type RotatingFile struct {
+	path        string
+	maxSize     int64
+	maxAge      time.Duration
+	maxBackups  int
+	rotateEvery time.Duration
+	compress    bool
+
+	mu         sync.Mutex
+	file       *os.File
+	size       int64
+	rotateAt   time.Time
+	rotateErr  error
+	millMu     sync.Mutex
+	millErr    error
+	millWaiter sync.WaitGroup
+}
+
+func OpenRotatingFile(cfg Config) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:        cfg.OutputPath,
+		maxSize:     int64(cfg.MaxSizeMB) * megabyte,
+		maxAge:      cfg.MaxAge,
+		maxBackups:  cfg.MaxBackups,
+		rotateEvery: cfg.RotateEvery,
+		compress:    cfg.Compress,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.needsRotation(int64(len(p))) {
+		err := f.rotate()
+		if err != nil && f.rotateErr == nil {
+			fmt.Fprintf(os.Stderr, "logger: %s: %v, writing to the current file\n", f.path, err)
+		}
+		f.rotateErr = err
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Close closes the file and waits for the compression and removal of
+// backups, returning their errors.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.millWaiter.Wait()
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+	return errors.Join(err, f.millErr)
+}
+
+// Rotate renames the current file to a backup and opens a new one.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Reopen closes the current file and opens OutputPath again, so that a file
+// moved away by an external tool like logrotate is replaced by a fresh one.
+// Writes are blocked during the swap and never go to a closed file; the
+// current file is kept when OutputPath cannot be opened.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	previous := f.file
+	if err := f.open(); err != nil {
+		return err
+	}
+	return closeLogFile(previous)
+}
+
+func (f *RotatingFile) needsRotation(writeLen int64) bool {
+	if f.maxSize > 0 && f.size > 0 && f.size+writeLen > f.maxSize {
+		return true
+	}
+	return !f.rotateAt.IsZero() && !time.Now().Before(f.rotateAt)
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	if f.rotateEvery > 0 {
+		f.rotateAt = time.Now().Truncate(f.rotateEvery).Add(f.rotateEvery)
+	}
+	return nil
+}
+
+// rotate renames the current file to a backup while it is still open, so
+// that a failed rename or open leaves it in place for the next writes.
+func (f *RotatingFile) rotate() error {
+	if err := os.Rename(f.path, f.backupName(time.Now())); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rename log file: %w", err)
+	}
+	previous := f.file
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.millWaiter.Add(1)
+	go f.mill()
+	return closeLogFile(previous)
+}
+
+func closeLogFile(file *os.File) error {
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	return nil
+}
+
+// backupName returns the name of a backup rotated at t, moved forward by a
+// millisecond while a backup of that time exists so none is overwritten.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext)
+	for t = t.UTC(); ; t = t.Add(time.Millisecond) {
+		name := filepath.Join(dir, prefix+"-"+t.Format(backupTimeFormat)+ext)
+		if !exists(name) && !exists(name+compressSuffix) {
+			return name
+		}
+	}
+}
+
+// exists reports whether path exists. A path that cannot be stat is taken as
+// free, so that the rename reports the error instead of looping.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// mill compresses fresh backups and removes the ones exceeding retention limits.
+func (f *RotatingFile) mill() {
+	defer f.millWaiter.Done()
+
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		f.millErr = errors.Join(f.millErr, err)
+		return
+	}
+
+	var remove []backupFile
+	if f.maxBackups > 0 && len(backups) > f.maxBackups {
+		remove = append(remove, backups[f.maxBackups:]...)
+		backups = backups[:f.maxBackups]
+	}
+	if f.maxAge > 0 {
+		cutoff := time.Now().Add(-f.maxAge)
+		kept := backups[:0]
+		for _, b := range backups {
+			if b.timestamp.Before(cutoff) {
+				remove = append(remove, b)
+			} else {
+				kept = append(kept, b)
+			}
+		}
+		backups = kept
+	}
+
+	for _, b := range remove {
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			f.millErr = errors.Join(f.millErr, fmt.Errorf("failed to remove log backup: %w", err))
+		}
+	}
+
+	if f.compress {
+		for _, b := range backups {
+			if strings.HasSuffix(b.path, compressSuffix) {
+				continue
+			}
+			if err := compressFile(b.path); err != nil {
+				f.millErr = errors.Join(f.millErr, fmt.Errorf("failed to compress log backup: %w", err))
+			}
+		}
+	}
+}
+
+type backupFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// backups returns rotated files of the current log, newest first. Their
+// timestamps are in UTC, see backupName.
+func (f *RotatingFile) backups() ([]backupFile, error) {
+	dir := filepath.Dir(f.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	var backups []backupFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name[len(prefix):], compressSuffix), ext)
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), timestamp: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+	return backups, nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + compressSuffix)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + compressSuffix)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + compressSuffix)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,300 @@
+package logger_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const backupLayout = "2006-01-02T15-04-05.000"
+
+func openRotatingFile(t *testing.T, cfg lg.Config) (*lg.RotatingFile, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg.OutputPath = filepath.Join(dir, "app.log")
+	f, err := lg.OpenRotatingFile(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	return f, dir
+}
+
+// backupNames returns the names of the backups in dir, oldest first.
+func backupNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "app-") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readLog returns the contents of a log file, decompressing backups.
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	if strings.HasSuffix(path, ".gz") {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		data, err = io.ReadAll(r)
+		require.NoError(t, err)
+	}
+	return string(data)
+}
+
+// writeBackup creates a backup of app.log rotated at the given time.
+func writeBackup(t *testing.T, dir string, at time.Time, suffix string) string {
+	t.Helper()
+	name := "app-" + at.UTC().Format(backupLayout) + ".log" + suffix
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("old\n"), 0644))
+	return name
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{MaxSizeMB: 1})
+	line := strings.Repeat("x", 1023) + "\n"
+
+	for range 1024 {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.Empty(t, backupNames(t, dir))
+
+	_, err := f.Write([]byte("next\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.Len(t, backups, 1)
+	require.Equal(t, strings.Repeat(line, 1024), readLog(t, filepath.Join(dir, backups[0])))
+	require.Equal(t, "next\n", readLog(t, filepath.Join(dir, "app.log")))
+}
+
+func TestRotatingFileTime(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{RotateEvery: 50 * time.Millisecond})
+
+	_, err := f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	time.Sleep(60 * time.Millisecond)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.Len(t, backups, 1)
+	require.Equal(t, "first\n", readLog(t, filepath.Join(dir, backups[0])))
+	require.Equal(t, "second\n", readLog(t, filepath.Join(dir, "app.log")))
+}
+
+func TestRotatingFileMaxBackups(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{MaxBackups: 2})
+	now := time.Now()
+	writeBackup(t, dir, now.Add(-3*time.Hour), "")
+	kept := writeBackup(t, dir, now.Add(-time.Hour), ".gz")
+	writeBackup(t, dir, now.Add(-2*time.Hour), "")
+
+	_, err := f.Write([]byte("current\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.Len(t, backups, 2)
+	require.Equal(t, kept, backups[0])
+	require.Equal(t, "current\n", readLog(t, filepath.Join(dir, backups[1])))
+}
+
+func TestRotatingFileMaxAge(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{MaxAge: 2 * time.Hour})
+	now := time.Now()
+	writeBackup(t, dir, now.Add(-3*time.Hour), ".gz")
+	kept := writeBackup(t, dir, now.Add(-time.Hour), "")
+
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.Len(t, backups, 2)
+	require.Equal(t, kept, backups[0])
+}
+
+// Backups are named and read back in UTC, so the zone of the process does
+// not shift MaxAge.
+func TestRotatingFileMaxAgeLocalZone(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	f, dir := openRotatingFile(t, lg.Config{MaxAge: 2 * time.Hour})
+	_, err := f.Write([]byte("current\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.Len(t, backups, 1)
+	require.Equal(t, "current\n", readLog(t, filepath.Join(dir, backups[0])))
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{Compress: true})
+
+	_, err := f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.Len(t, backups, 2)
+	for _, name := range backups {
+		require.True(t, strings.HasSuffix(name, ".log.gz"), name)
+	}
+	require.Equal(t, "first\n", readLog(t, filepath.Join(dir, backups[0])))
+	require.Equal(t, "second\n", readLog(t, filepath.Join(dir, backups[1])))
+	require.Empty(t, readLog(t, filepath.Join(dir, "app.log")))
+}
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	const writers, lines = 8, 2000
+	f, dir := openRotatingFile(t, lg.Config{MaxSizeMB: 1})
+	padding := strings.Repeat("x", 200)
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range lines {
+				if _, err := fmt.Fprintf(f, "%d %d %s\n", w, i, padding); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, f.Close())
+
+	backups := backupNames(t, dir)
+	require.GreaterOrEqual(t, len(backups), 2)
+	seen := map[string]bool{}
+	for _, name := range append(backups, "app.log") {
+		content := readLog(t, filepath.Join(dir, name))
+		require.LessOrEqual(t, len(content), 1<<20, name)
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			fields := strings.Fields(line)
+			require.Len(t, fields, 3, name)
+			require.Equal(t, padding, fields[2])
+			seen[fields[0]+" "+fields[1]] = true
+		}
+	}
+	require.Len(t, seen, writers*lines)
+}
+
+// A log directory removed under the writer is created again by the next
+// rotation instead of closing the writer for good.
+func TestRotatingFileRecreatesDirectory(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{})
+	require.NoError(t, os.RemoveAll(dir))
+
+	require.NoError(t, f.Rotate())
+	_, err := f.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, "after\n", readLog(t, filepath.Join(dir, "app.log")))
+
+	_, err = f.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+	require.ErrorIs(t, f.Rotate(), os.ErrClosed)
+}
+
+// A file moved away by logrotate keeps the writes until Reopen, which
+// creates a fresh file at the original path.
+func TestRotatingFileReopen(t *testing.T) {
+	f, dir := openRotatingFile(t, lg.Config{})
+	path := filepath.Join(dir, "app.log")
+	moved := filepath.Join(dir, "app.log.1")
+
+	_, err := f.Write([]byte("before\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(path, moved))
+	_, err = f.Write([]byte("moved\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, f.Reopen())
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.Equal(t, "before\nmoved\n", readLog(t, moved))
+	require.Equal(t, "after\n", readLog(t, path))
+	require.ErrorIs(t, f.Reopen(), os.ErrClosed)
+}
+
+// captureStderr returns what fn writes to os.Stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	fn()
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+// A failed rotation does not fail the write, it is reported on stderr once
+// until a rotation succeeds again.
+func TestRotatingFileRotationFailure(t *testing.T) {
+	const every = 20 * time.Millisecond
+	f, dir := openRotatingFile(t, lg.Config{RotateEvery: every})
+	_, err := f.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	// The directory replaced by a file fails the rename of the log file.
+	require.NoError(t, os.RemoveAll(dir))
+	require.NoError(t, os.WriteFile(dir, nil, 0644))
+	time.Sleep(every)
+	stderr := captureStderr(t, func() {
+		for _, p := range []string{"failed\n", "again\n"} {
+			n, err := f.Write([]byte(p))
+			require.NoError(t, err)
+			require.Equal(t, len(p), n)
+		}
+	})
+	require.Equal(t, 1, strings.Count(stderr, "failed to rename log file"), stderr)
+
+	require.NoError(t, os.Remove(dir))
+	time.Sleep(every)
+	stderr = captureStderr(t, func() {
+		_, err = f.Write([]byte("rotated\n"))
+		require.NoError(t, err)
+	})
+	require.Empty(t, stderr)
+	require.NoError(t, f.Close())
+	require.Equal(t, "rotated\n", readLog(t, filepath.Join(dir, "app.log")))
+}
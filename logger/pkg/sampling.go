@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DroppedKey = "dropped"
+
+	samplingCounters      = 4096
+	defaultSampleInterval = time.Second
+	defaultReportInterval = time.Minute
+)
+
+// SamplingPolicy limits records with the same level and message: in every
+// Interval (default 1s) the first First records are logged, then every
+// Thereafter-th one, none when Thereafter is zero. The zero policy logs everything.
+type SamplingPolicy struct {
+	First      int
+	Thereafter int
+	Interval   time.Duration
+}
+
+// SamplingConfig is the policy for all levels with per-level overrides keyed
+// by level name, e.g. {"error": {}} never samples errors. The number of
+// dropped records is logged every ReportInterval (default 1m) and on Close.
+type SamplingConfig struct {
+	SamplingPolicy
+	Levels         map[string]SamplingPolicy
+	ReportInterval time.Duration
+}
+
+var samplingLevels = []string{"debug", "info", "warn", "error"}
+
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// inc increments the counter, starting a new interval when the current one is over.
+func (c *samplingCounter) inc(now int64, interval time.Duration) uint64 {
+	resetAt := c.resetAt.Load()
+	if now > resetAt {
+		if c.resetAt.CompareAndSwap(resetAt, now+interval.Nanoseconds()) {
+			c.count.Store(1)
+			return 1
+		}
+	}
+	return c.count.Add(1)
+}
+
+type levelSampler struct {
+	policy   SamplingPolicy
+	counters [samplingCounters]samplingCounter
+	dropped  atomic.Uint64
+}
+
+func (s *levelSampler) sample(msg string, now int64) bool {
+	h := fnv.New32a()
+	h.Write([]byte(msg))
+	n := s.counters[h.Sum32()%samplingCounters].inc(now, s.policy.Interval)
+
+	first := uint64(s.policy.First)
+	if n <= first || (s.policy.Thereafter > 0 && (n-first)%uint64(s.policy.Thereafter) == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// Sampler makes the sampling decisions of both backends and periodically
+// reports the dropped records.
+type Sampler struct {
+	levels         map[string]*levelSampler
+	reportInterval time.Duration
+
+	mu     sync.Mutex
+	report func(fields []Field)
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewSampler returns nil for a nil cfg.
+func NewSampler(cfg *SamplingConfig) (*Sampler, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	policies := make(map[string]SamplingPolicy, len(samplingLevels))
+	for _, level := range samplingLevels {
+		policies[level] = cfg.SamplingPolicy
+	}
+	for name, policy := range cfg.Levels {
+		level := strings.ToLower(name)
+		if level == "warning" {
+			level = "warn"
+		}
+		if _, ok := policies[level]; !ok {
+			return nil, fmt.Errorf("unknown sampling level: %s", name)
+		}
+		policies[level] = policy
+	}
+
+	s := &Sampler{
+		levels:         make(map[string]*levelSampler, len(policies)),
+		reportInterval: cfg.ReportInterval,
+	}
+	if s.reportInterval <= 0 {
+		s.reportInterval = defaultReportInterval
+	}
+	for level, policy := range policies {
+		if policy.First < 0 || policy.Thereafter < 0 {
+			return nil, fmt.Errorf("invalid sampling policy for %s level", level)
+		}
+		if policy == (SamplingPolicy{}) {
+			continue
+		}
+		if policy.Interval <= 0 {
+			policy.Interval = defaultSampleInterval
+		}
+		s.levels[level] = &levelSampler{policy: policy}
+	}
+	return s, nil
+}
+
+// Sample reports whether a record should be logged. Levels above error are
+// sampled as error.
+func (s *Sampler) Sample(level, msg string) bool {
+	level = strings.ToLower(level)
+	switch level {
+	case "dpanic", "panic", "fatal":
+		level = "error"
+	}
+	ls, ok := s.levels[level]
+	if !ok {
+		return true
+	}
+	return ls.sample(msg, time.Now().UnixNano())
+}
+
+// Start calls report with the dropped counts per level every report interval
+// in which records were dropped, until Close.
+func (s *Sampler) Start(report func(fields []Field)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.report = report
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.reportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.flush()
+			case <-s.stop:
+				s.flush()
+				return
+			}
+		}
+	}()
+}
+
+// Dropped returns the number of records dropped since the last report.
+func (s *Sampler) Dropped() map[string]uint64 {
+	dropped := make(map[string]uint64, len(s.levels))
+	for level, ls := range s.levels {
+		dropped[level] = ls.dropped.Load()
+	}
+	return dropped
+}
+
+func (s *Sampler) flush() {
+	var fields []Field
+	for _, level := range samplingLevels {
+		if ls, ok := s.levels[level]; ok {
+			if n := ls.dropped.Swap(0); n > 0 {
+				fields = append(fields, Int64(level, int64(n)))
+			}
+		}
+	}
+	if len(fields) > 0 {
+		s.report([]Field{Group(DroppedKey, fields...)})
+	}
+}
+
+// Close stops reporting after a final report. It is safe on a nil Sampler.
+func (s *Sampler) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+}
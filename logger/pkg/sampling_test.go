@@ -0,0 +1,154 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// countMessages counts the records per message.
+func countMessages(records []map[string]any) map[string]int {
+	counts := map[string]int{}
+	for _, msg := range recordMessages(records) {
+		counts[msg]++
+	}
+	return counts
+}
+
+// droppedReports returns the dropped counts of the sampling reports.
+func droppedReports(t *testing.T, records []map[string]any) []map[string]any {
+	t.Helper()
+	var reports []map[string]any
+	for _, record := range records {
+		if msg, _ := record[lg.MessageKey].(string); msg == lg.MsgRecordsDropped {
+			require.Equal(t, "warn", record[lg.LevelKey])
+			dropped, _ := record[lg.DroppedKey].(map[string]any)
+			reports = append(reports, dropped)
+		}
+	}
+	return reports
+}
+
+func TestSampling(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			sink, read := fileSink(t, lg.FormatJSON)
+			factory, err := b.newFactory(lg.Config{
+				LogLevel: "debug",
+				Sinks:    []lg.SinkConfig{sink},
+				Sampling: &lg.SamplingConfig{
+					SamplingPolicy: lg.SamplingPolicy{First: 2, Thereafter: 3, Interval: time.Hour},
+					Levels: map[string]lg.SamplingPolicy{
+						"debug": {First: 1, Interval: time.Hour},
+						"error": {},
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			logger := factory.Named(context.Background(), "worker")
+			for range 10 {
+				logger.Info("hot")
+				logger.Info("other")
+				logger.Debug("verbose")
+				logger.Warning("slow")
+				logger.Error("failed")
+			}
+			require.NoError(t, factory.Close())
+
+			records := jsonRecords(t, read())
+			counts := countMessages(records)
+			// The 1st, 2nd, 5th and 8th of every message are logged.
+			require.Equal(t, 4, counts["hot"])
+			require.Equal(t, 4, counts["other"])
+			require.Equal(t, 4, counts["slow"])
+			require.Equal(t, 1, counts["verbose"])
+			require.Equal(t, 10, counts["failed"])
+
+			reports := droppedReports(t, records)
+			require.Len(t, reports, 1)
+			require.Equal(t, map[string]any{"debug": 9.0, "info": 12.0, "warn": 6.0}, reports[0])
+		})
+	}
+}
+
+func TestSamplingReportInterval(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			sink, read := fileSink(t, lg.FormatJSON)
+			factory, err := b.newFactory(lg.Config{
+				LogLevel: "info",
+				Sinks:    []lg.SinkConfig{sink},
+				Sampling: &lg.SamplingConfig{
+					SamplingPolicy: lg.SamplingPolicy{First: 1, Interval: time.Hour},
+					ReportInterval: 20 * time.Millisecond,
+				},
+			})
+			require.NoError(t, err)
+
+			logger := factory.Named(context.Background(), "worker")
+			for range 3 {
+				logger.Info("hot")
+			}
+			require.Eventually(t, func() bool {
+				return strings.Contains(read(), lg.MsgRecordsDropped)
+			}, receiveTimeout, 10*time.Millisecond)
+
+			// Intervals without drops are not reported, nor is the final
+			// report on Close.
+			time.Sleep(60 * time.Millisecond)
+			require.NoError(t, factory.Close())
+			reports := droppedReports(t, jsonRecords(t, read()))
+			require.Equal(t, []map[string]any{{"info": 2.0}}, reports)
+		})
+	}
+}
+
+// Reports are Warn records, so they skip sinks and factories above warn.
+func TestSamplingReportLevel(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			infoSink, readInfo := fileSink(t, lg.FormatJSON)
+			errorSink, readError := fileSink(t, lg.FormatJSON)
+			errorSink.Level = "error"
+			sampling := &lg.SamplingConfig{SamplingPolicy: lg.SamplingPolicy{First: 1, Interval: time.Hour}}
+
+			factory, err := b.newFactory(lg.Config{
+				LogLevel: "info",
+				Sinks:    []lg.SinkConfig{infoSink, errorSink},
+				Sampling: sampling,
+			})
+			require.NoError(t, err)
+			logger := factory.Named(context.Background(), "worker")
+			for range 3 {
+				logger.Info("hot")
+				logger.Error("failed")
+			}
+			require.NoError(t, factory.Close())
+
+			require.Equal(t, []map[string]any{{"info": 2.0, "error": 2.0}}, droppedReports(t, jsonRecords(t, readInfo())))
+			require.Empty(t, droppedReports(t, jsonRecords(t, readError())))
+
+			errorOnly, read := fileSink(t, lg.FormatJSON)
+			factory, err = b.newFactory(lg.Config{
+				LogLevel: "error",
+				Sinks:    []lg.SinkConfig{errorOnly},
+				Sampling: sampling,
+			})
+			require.NoError(t, err)
+			logger = factory.Named(context.Background(), "worker")
+			for range 3 {
+				logger.Error("failed")
+			}
+			require.NoError(t, factory.Close())
+			records := jsonRecords(t, read())
+			require.Equal(t, 1, countMessages(records)["failed"])
+			require.Empty(t, droppedReports(t, records))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+// Keys of the records written as JSON or logfmt, shared by both backends.
+// Durations are written as milliseconds in JSON and stack traces as groups
+// of frames, see Stack.
+const (
+	TimeKey    = "timestamp"
+	LevelKey   = "level"
+	CallerKey  = "caller"
+	MessageKey = "message"
+	ServiceKey = "service"
+	VersionKey = "version"
+
+	// TimeLayout is ISO8601 with milliseconds.
+	TimeLayout = "2006-01-02T15:04:05.000Z0700"
+)
+
+// ServiceFields returns the service and version fields of every record,
+// omitting empty values.
+func ServiceFields(cfg Config) []Field {
+	var fields []Field
+	if cfg.ServiceName != "" {
+		fields = append(fields, String(ServiceKey, cfg.ServiceName))
+	}
+	if cfg.Version != "" {
+		fields = append(fields, String(VersionKey, cfg.Version))
+	}
+	return fields
+}
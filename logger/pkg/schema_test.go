@@ -0,0 +1,68 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+var timestampPattern = regexp.MustCompile(`(timestamp"?[:=]"?)\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}(Z|[+-]\d{4})`)
+
+// goldenJSON and goldenLogfmt are the records of logGolden, with %d the
+// line of its calls and TIME the timestamps.
+const (
+	goldenJSON = `{"timestamp":"TIME","level":"info","caller":"pkg/schema_test.go:%[1]d","message":"worker: start","service":"svc","version":"1.0"}
+{"timestamp":"TIME","level":"warn","caller":"pkg/schema_test.go:%[2]d","message":"worker: quote \"x\" and\nnewline","service":"svc","version":"1.0","empty":"","space":"a b","n":3,"f":1.5,"ok":true,"d":1500,"at":"2026-01-02T03:04:05.006Z","g":{"k":"v","h":{"i":1}},"error":"boom","list":["a","b"],"nil":null}
+`
+	goldenLogfmt = `timestamp=TIME level=info caller=pkg/schema_test.go:%[1]d message="worker: start" service=svc version=1.0
+timestamp=TIME level=warn caller=pkg/schema_test.go:%[2]d message="worker: quote \"x\" and\nnewline" service=svc version=1.0 empty="" space="a b" n=3 f=1.5 ok=true d=1.5s at=2026-01-02T03:04:05.006Z g.k=v g.h.i=1 error=boom list="[\"a\",\"b\"]" nil=null
+`
+)
+
+// logGolden logs the records of the golden files and returns the lines of
+// its calls.
+func logGolden(factory lg.Factory) (int, int) {
+	logger := factory.Named(context.Background(), "worker")
+	line := lineOf(func() {})
+	logger.Warning("quote \"x\" and\nnewline",
+		lg.String("empty", ""),
+		lg.String("space", "a b"),
+		lg.Int("n", 3),
+		lg.Float64("f", 1.5),
+		lg.Bool("ok", true),
+		lg.Duration("d", 1500*time.Millisecond),
+		lg.Time("at", time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC)),
+		lg.Group("g", lg.String("k", "v"), lg.Group("h", lg.Int("i", 1))),
+		lg.Err(errors.New("boom")),
+		lg.Any("list", []string{"a", "b"}),
+		lg.Any("nil", nil))
+	return line - 1, line + 1
+}
+
+// The backends write the same records in the structured formats.
+func TestSchemaGolden(t *testing.T) {
+	for _, format := range []struct{ name, golden string }{
+		{lg.FormatJSON, goldenJSON},
+		{lg.FormatLogfmt, goldenLogfmt},
+	} {
+		for _, b := range backends {
+			t.Run(format.name+"/"+b.name, func(t *testing.T) {
+				sink, read := fileSink(t, format.name)
+				factory, err := b.newFactory(lg.Config{ServiceName: "svc", Version: "1.0", Sinks: []lg.SinkConfig{sink}})
+				require.NoError(t, err)
+				startLine, warnLine := logGolden(factory)
+				require.NoError(t, factory.Close())
+
+				want := fmt.Sprintf(format.golden, startLine, warnLine)
+				require.Equal(t, want, timestampPattern.ReplaceAllString(read(), "${1}TIME"))
+			})
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+
+	DestinationStderr = "stderr"
+	DestinationStdout = "stdout"
+
+	// ColorAuto colors the levels of text sinks on terminals, ColorAlways
+	// and ColorNever force them on and off.
+	ColorAuto   = ""
+	ColorAlways = "always"
+	ColorNever  = "never"
+
+	// MessageFilterKey is the key of sink filters matching the record message,
+	// the key of the message in the records.
+	MessageFilterKey = MessageKey
+)
+
+// SinkConfig is one output of a factory.
+type SinkConfig struct {
+	// Destination is "stderr" (default), "stdout", DestinationOTLP,
+	// DestinationSyslog, DestinationJournald or a file path rotated with the
+	// rotation settings of Config.
+	Destination string
+	// Format is FormatText, FormatJSON or FormatLogfmt, by default text for
+	// stderr and stdout and JSON for files. Structured sinks ignore it.
+	Format string
+	// Color is ColorAuto, ColorAlways or ColorNever for the text format.
+	Color string
+	// Level is the minimal level of the sink on top of the factory level.
+	Level string
+	// Include keeps only records matching one of the filters, Exclude drops
+	// records matching one of them, see SinkFilter.
+	Include []string
+	Exclude []string
+	// OTLP, Syslog and Journald configure the sinks of their destination,
+	// nil uses the defaults.
+	OTLP     *OTLPConfig
+	Syslog   *SyslogConfig
+	Journald *JournaldConfig
+}
+
+// SinkConfigs returns Sinks with defaults applied. Without Sinks the factory
+// writes text to stderr and, when OutputPath is set, JSON to OutputPath.
+func (c Config) SinkConfigs() ([]SinkConfig, error) {
+	sinks := c.Sinks
+	if len(sinks) == 0 {
+		sinks = []SinkConfig{{Destination: DestinationStderr}}
+		if c.OutputPath != "" {
+			sinks = append(sinks, SinkConfig{Destination: c.OutputPath})
+		}
+	}
+
+	configs := make([]SinkConfig, len(sinks))
+	for i, sink := range sinks {
+		var err error
+		if configs[i], err = sink.withDefaults(); err != nil {
+			return nil, err
+		}
+	}
+	return configs, nil
+}
+
+func (s SinkConfig) withDefaults() (SinkConfig, error) {
+	if s.Destination == "" {
+		s.Destination = DestinationStderr
+	}
+	if s.IsStructured() {
+		err := s.structuredDefaults()
+		return s, err
+	}
+	switch strings.ToLower(s.Color) {
+	case ColorAuto, ColorAlways, ColorNever:
+		s.Color = strings.ToLower(s.Color)
+	default:
+		return s, fmt.Errorf("unknown color %q of sink %s", s.Color, s.Destination)
+	}
+	switch strings.ToLower(s.Format) {
+	case "":
+		s.Format = FormatText
+		if !s.IsConsole() {
+			s.Format = FormatJSON
+		}
+	case FormatText, FormatJSON, FormatLogfmt:
+		s.Format = strings.ToLower(s.Format)
+	default:
+		return s, fmt.Errorf("unknown format %q of sink %s", s.Format, s.Destination)
+	}
+	return s, nil
+}
+
+// IsConsole reports whether the sink writes to stderr or stdout.
+func (s SinkConfig) IsConsole() bool {
+	return s.Destination == DestinationStderr || s.Destination == DestinationStdout
+}
+
+// IsOTLP reports whether the sink exports records to an OpenTelemetry
+// collector.
+func (s SinkConfig) IsOTLP() bool {
+	return s.Destination == DestinationOTLP
+}
+
+// IsStructured reports whether the sink sends records with their fields
+// instead of formatted lines: OTLP, syslog and journald sinks.
+func (s SinkConfig) IsStructured() bool {
+	switch s.Destination {
+	case DestinationOTLP, DestinationSyslog, DestinationJournald:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *SinkConfig) structuredDefaults() error {
+	switch s.Destination {
+	case DestinationOTLP:
+		if s.OTLP == nil {
+			s.OTLP = &OTLPConfig{}
+		}
+		_, err := s.OTLP.WithDefaults()
+		return err
+	case DestinationSyslog:
+		if s.Syslog == nil {
+			s.Syslog = &SyslogConfig{}
+		}
+		return s.Syslog.Validate()
+	default:
+		if s.Journald == nil {
+			s.Journald = &JournaldConfig{}
+		}
+		return nil
+	}
+}
+
+type filterRule struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+// SinkFilter selects the records of a sink. A filter is "message=pattern",
+// matching the message, "key=pattern", matching the value of a top-level
+// field, or "key", matching records having the field. Patterns are globs
+// where * matches any text and ? a single character, newlines included,
+// matched against the whole value: "message=*timeout*" is needed for
+// messages containing timeout, also on another line of the message.
+// The message is matched as written, prefixed with the function name of the
+// logger: "message=*: start" matches the start records of all loggers.
+type SinkFilter struct {
+	include []filterRule
+	exclude []filterRule
+}
+
+// NewSinkFilter returns nil when there are no filters.
+func NewSinkFilter(include, exclude []string) (*SinkFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	f := &SinkFilter{}
+	var err error
+	if f.include, err = parseFilterRules(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = parseFilterRules(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseFilterRules(filters []string) ([]filterRule, error) {
+	rules := make([]filterRule, 0, len(filters))
+	for _, filter := range filters {
+		key, pattern, hasPattern := strings.Cut(filter, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid sink filter %q, expected key or key=pattern", filter)
+		}
+		rule := filterRule{key: key}
+		if hasPattern {
+			re, err := regexp.Compile(globToRegexp(pattern))
+			if err != nil {
+				return nil, fmt.Errorf("invalid sink filter %q: %w", filter, err)
+			}
+			rule.pattern = re
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	// (?s) lets * and ? match newlines of multi-line messages and values.
+	b.WriteString("(?s)^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Match reports whether a record passes the filter, lookup returns the
+// string value of a top-level field.
+func (f *SinkFilter) Match(msg string, lookup func(key string) (string, bool)) bool {
+	if len(f.include) > 0 && !matchAny(f.include, msg, lookup) {
+		return false
+	}
+	return !matchAny(f.exclude, msg, lookup)
+}
+
+func matchAny(rules []filterRule, msg string, lookup func(key string) (string, bool)) bool {
+	for _, rule := range rules {
+		value, ok := msg, true
+		if rule.key != MessageFilterKey {
+			value, ok = lookup(rule.key)
+		}
+		if ok && (rule.pattern == nil || rule.pattern.MatchString(value)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package logger_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// logSinkRecords logs the records matched by the sinks of TestSinks.
+func logSinkRecords(logger lg.Logger) {
+	logger.Info("keep this")
+	logger.Warning("slow")
+	logger.Info("audit", lg.Bool("audit", true))
+	logger.Info("login", lg.String("user", "jane"))
+	logger.Info("login", lg.String("user", "bot-1"))
+	logger.WithFields(lg.String("user", "ann")).Info("fields")
+	logger.Error("failed")
+}
+
+func TestSinks(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			warn, readWarn := fileSink(t, lg.FormatJSON)
+			warn.Level = "warn"
+			quiet, readQuiet := fileSink(t, lg.FormatLogfmt)
+			quiet.Exclude = []string{"message=*: start", "message=*: end"}
+			audit, readAudit := fileSink(t, lg.FormatJSON)
+			audit.Include = []string{"audit=true", "message=worker: keep *"}
+			users, readUsers := fileSink(t, lg.FormatText)
+			users.Include = []string{"user"}
+			users.Exclude = []string{"user=bot*"}
+			// Globs are anchored and the message carries the logger prefix.
+			unprefixed, readUnprefixed := fileSink(t, lg.FormatJSON)
+			unprefixed.Include = []string{"message=keep *", "message=*keep"}
+
+			factory, err := b.newFactory(lg.Config{
+				LogLevel: "debug",
+				Sinks:    []lg.SinkConfig{warn, quiet, audit, users, unprefixed},
+			})
+			require.NoError(t, err)
+			logger := factory.Named(context.Background(), "worker")
+			logSinkRecords(logger)
+			logger.End()
+			require.NoError(t, factory.Close())
+
+			require.Equal(t, []string{"slow", "failed"}, recordMessages(jsonRecords(t, readWarn())))
+			require.Equal(t, []string{"keep this", "audit"}, recordMessages(jsonRecords(t, readAudit())))
+			require.Empty(t, readUnprefixed())
+
+			lines := strings.Split(strings.TrimSpace(readQuiet()), "\n")
+			require.Len(t, lines, 7)
+			for _, line := range lines {
+				require.Contains(t, line, `level=`)
+				require.Contains(t, line, `message="worker: `)
+				require.NotContains(t, line, `worker: start"`)
+				require.NotContains(t, line, `worker: end"`)
+			}
+
+			text := readUsers()
+			require.Len(t, strings.Split(strings.TrimSpace(text), "\n"), 2)
+			require.Contains(t, text, "worker: login")
+			require.Contains(t, text, "worker: fields")
+			require.NotContains(t, text, "bot-1")
+			require.False(t, strings.HasPrefix(text, "{"))
+		})
+	}
+}
+
+func TestSinkFilterInvalid(t *testing.T) {
+	for _, filters := range [][]string{{""}, {"=value"}, {" =*"}} {
+		_, err := lg.NewSinkFilter(filters, nil)
+		require.Error(t, err, filters)
+		_, err = lg.NewSinkFilter(nil, filters)
+		require.Error(t, err, filters)
+	}
+	filter, err := lg.NewSinkFilter(nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, filter)
+}
+
+// Globs match across the lines of wrapped errors and panics.
+func TestSinkFilterMultiline(t *testing.T) {
+	filter, err := lg.NewSinkFilter([]string{"message=*timeout*", "error=*refused?retry*"}, nil)
+	require.NoError(t, err)
+	lookup := func(value string) func(string) (string, bool) {
+		return func(key string) (string, bool) { return value, key == lg.ErrorKey }
+	}
+
+	require.True(t, filter.Match("worker: call failed\ncaused by: timeout\nstack", lookup("")))
+	require.True(t, filter.Match("worker: call failed", lookup("dial: refused\nretry later")))
+	require.False(t, filter.Match("worker: call failed\ncaused by: refused", lookup("")))
+}
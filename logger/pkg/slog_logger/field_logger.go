@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+var (
+	_ lg.Factory         = (*LoggerFactory)(nil)
+	_ lg.Reopener        = (*LoggerFactory)(nil)
+	_ lg.LevelController = (*LoggerFactory)(nil)
+	_ lg.Logger          = (*fieldLogger)(nil)
+	_ lg.CallerSkipper   = (*fieldLogger)(nil)
+)
+
+// fieldLogger adapts Logger to the backend-neutral lg.Logger interface.
+type fieldLogger struct {
+	logger *Logger
+}
+
+func (f *LoggerFactory) Get(ctx context.Context, fields ...lg.Field) lg.Logger {
+	pc := f.callerPC()
+	return &fieldLogger{logger: f.newLogger(ctx, pc, lg.FunctionName(pc), fieldsToAttrs(fields))}
+}
+
+func (f *LoggerFactory) Named(ctx context.Context, name string, fields ...lg.Field) lg.Logger {
+	return &fieldLogger{logger: f.newLogger(ctx, f.callerPC(), name, fieldsToAttrs(fields))}
+}
+
+func (l *fieldLogger) WithFields(fields ...lg.Field) lg.Logger {
+	return &fieldLogger{logger: l.logger.WithFields(fieldsToAttrs(fields)...)}
+}
+
+func (l *fieldLogger) WithCallerSkip(skip int) lg.Logger {
+	return &fieldLogger{logger: l.logger.WithCallerSkip(skip)}
+}
+
+func (l *fieldLogger) Debug(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), slog.LevelDebug, msg, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) Info(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), slog.LevelInfo, msg, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) Warning(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), slog.LevelWarn, msg, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) Error(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), slog.LevelError, msg, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorIn(funcName string, err error, fields ...lg.Field) {
+	l.logger.errorIn(l.logger.callerPC(), funcName, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQL(operation SQLErrorType, table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), operation, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLSelect(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLSelect, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLInsert(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLInsert, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLUpdate(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLUpdate, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLDelete(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLDelete, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLBegin(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLBegin, "", err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLCommit(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLCommit, "", err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLRollback(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLRollback, "", err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLDDL(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLDDL, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLMigration(migration string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLMigration, migration, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLUpsert(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLUpsert, table, err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) ErrorSQLConnection(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLConnection, "", err, fieldsToAttrs(fields))
+}
+
+func (l *fieldLogger) End() {
+	l.logger.end(l.logger.callerPC(), recover(), nil)
+}
+
+func (l *fieldLogger) EndWithError(errPtr *error) {
+	l.logger.end(l.logger.callerPC(), recover(), errPtr)
+}
+
+func fieldsToAttrs(fields []lg.Field) []slog.Attr {
+	if len(fields) == 0 {
+		return nil
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, fieldToAttr(field))
+	}
+	return attrs
+}
+
+func fieldToAttr(field lg.Field) slog.Attr {
+	if group, ok := field.Value.(lg.GroupValue); ok {
+		return slog.Attr{Key: field.Key, Value: slog.GroupValue(fieldsToAttrs(group)...)}
+	}
+	return slog.Any(field.Key, field.Value)
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// filterHandler passes the records matching lg.SinkFilter to the wrapped
+// handler. Attributes of the logger and of the record are matched, except
+// those added inside a group.
+type filterHandler struct {
+	handler slog.Handler
+	filter  *lg.SinkFilter
+	attrs   []slog.Attr
+	grouped bool
+}
+
+// NewFilterHandler returns handler itself when filter is nil.
+func NewFilterHandler(handler slog.Handler, filter *lg.SinkFilter) slog.Handler {
+	if filter == nil {
+		return handler
+	}
+	return &filterHandler{handler: handler, filter: filter}
+}
+
+func (h *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *filterHandler) Handle(ctx context.Context, r slog.Record) error {
+	lookup := func(key string) (string, bool) {
+		value, found := "", false
+		if !h.grouped {
+			r.Attrs(func(attr slog.Attr) bool {
+				if attr.Key == key {
+					value, found = attr.Value.Resolve().String(), true
+				}
+				return !found
+			})
+		}
+		for i := len(h.attrs) - 1; i >= 0 && !found; i-- {
+			if h.attrs[i].Key == key {
+				value, found = h.attrs[i].Value.Resolve().String(), true
+			}
+		}
+		return value, found
+	}
+
+	if !h.filter.Match(r.Message, lookup) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	derived := *h
+	derived.handler = h.handler.WithAttrs(attrs)
+	if !h.grouped {
+		derived.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+	}
+	return &derived
+}
+
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.handler = h.handler.WithGroup(name)
+	derived.grouped = true
+	return &derived
+}
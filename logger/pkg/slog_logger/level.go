@@ -0,0 +1,29 @@
+package logger
+
+import "log/slog"
+
+// floorLevel is the handler level: the lowest of the factory level and
+// the level rules, so that Loggers matched by a rule can log below LogLevel.
+// Each Logger then applies its own level.
+type floorLevel struct {
+	level *slog.LevelVar
+	rules []slog.Level
+}
+
+func (f *floorLevel) Level() slog.Level {
+	level := f.level.Level()
+	for _, rule := range f.rules {
+		level = min(level, rule)
+	}
+	return level
+}
+
+// sinkLevel raises the handler level of a sink to its own minimal level.
+type sinkLevel struct {
+	level slog.Leveler
+	min   slog.Level
+}
+
+func (s *sinkLevel) Level() slog.Level {
+	return max(s.level.Level(), s.min)
+}
@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// ConsoleHandlerOptions configure NewConsoleHandler.
+type ConsoleHandlerOptions struct {
+	Level     slog.Leveler
+	AddSource bool
+
+	// Color colors the levels, use lg.IsTerminal to enable it on terminals only.
+	Color bool
+
+	// CallerPrefix is written before the caller, see lg.CallerPrefix.
+	CallerPrefix string
+}
+
+// lineHandler writes a record per line, either as strict logfmt or as
+// aligned console columns followed by logfmt attributes.
+type lineHandler struct {
+	w       io.Writer
+	mu      *sync.Mutex
+	opts    slog.HandlerOptions
+	console *ConsoleHandlerOptions
+	attrs   []byte
+	groups  []string
+}
+
+// NewLogfmtHandler writes records as logfmt key=value pairs. Groups are
+// flattened into dotted keys, keys are sanitized and values quoted when
+// needed, so that every line is valid logfmt.
+func NewLogfmtHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	h := &lineHandler{w: w, mu: new(sync.Mutex)}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// NewConsoleHandler writes records for humans: time, padded level, short
+// caller and message in aligned columns, then the attributes as logfmt and
+// stack traces as indented blocks below the line.
+func NewConsoleHandler(w io.Writer, opts *ConsoleHandlerOptions) slog.Handler {
+	h := &lineHandler{w: w, mu: new(sync.Mutex), console: &ConsoleHandlerOptions{}}
+	if opts != nil {
+		*h.console = *opts
+		h.opts = slog.HandlerOptions{Level: opts.Level, AddSource: opts.AddSource}
+	}
+	return h
+}
+
+func (h *lineHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *lineHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf []byte
+	if h.console != nil {
+		buf = h.appendConsoleHeader(buf, r)
+	} else {
+		buf = h.appendLogfmtHeader(buf, r)
+	}
+
+	buf = append(buf, h.attrs...)
+	var stacks []string
+	prefix := groupPrefix(h.groups)
+	r.Attrs(func(attr slog.Attr) bool {
+		if s, ok := attr.Value.Any().(lg.Stack); ok && h.console != nil && attr.Key == lg.StacktraceKey {
+			stacks = append(stacks, s.String())
+			return true
+		}
+		buf = h.appendAttr(buf, h.groups, prefix, attr)
+		return true
+	})
+	if h.console == nil && len(buf) > 0 {
+		buf = buf[1:]
+	}
+	buf = append(buf, '\n')
+	for _, stack := range stacks {
+		buf = lg.AppendIndented(buf, stack)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf)
+	return err
+}
+
+func (h *lineHandler) appendConsoleHeader(buf []byte, r slog.Record) []byte {
+	if !r.Time.IsZero() {
+		buf = append(buf, r.Time.Format(lg.TimeLayout)...)
+		buf = append(buf, ' ')
+	}
+	buf = lg.AppendConsoleLevel(buf, r.Level.String(), h.console.Color)
+	if h.console.AddSource && r.PC != 0 {
+		buf = append(buf, ' ')
+		buf = lg.AppendConsoleCaller(buf, h.console.CallerPrefix+shortCaller(r.PC))
+	}
+	buf = append(buf, ' ')
+	return append(buf, r.Message...)
+}
+
+func (h *lineHandler) appendLogfmtHeader(buf []byte, r slog.Record) []byte {
+	if !r.Time.IsZero() {
+		buf = h.appendAttr(buf, nil, "", slog.Time(slog.TimeKey, r.Time))
+	}
+	buf = h.appendAttr(buf, nil, "", slog.String(slog.LevelKey, strings.ToLower(r.Level.String())))
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		source := &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+		buf = h.appendAttr(buf, nil, "", slog.Any(slog.SourceKey, source))
+	}
+	return h.appendAttr(buf, nil, "", slog.String(slog.MessageKey, r.Message))
+}
+
+// appendAttr appends attr as " key=value" with the keys of groups joined by
+// dots.
+func (h *lineHandler) appendAttr(buf []byte, groups []string, prefix string, attr slog.Attr) []byte {
+	if stack, ok := attr.Value.Any().(lg.Stack); ok {
+		attr.Value = slog.StringValue(stack.String())
+	}
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+	if attr.Equal(slog.Attr{}) {
+		return buf
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			buf = h.appendAttr(buf, groups, prefix, member)
+		}
+		return buf
+	}
+
+	buf = append(buf, ' ')
+	buf = lg.AppendLogfmtKey(buf, prefix+attr.Key)
+	buf = append(buf, '=')
+	return lg.AppendLogfmtValue(buf, valueString(attr.Value))
+}
+
+func valueString(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return strconv.FormatInt(v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.FormatUint(v.Uint64(), 10)
+	case slog.KindFloat64:
+		return strconv.FormatFloat(v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		return strconv.FormatBool(v.Bool())
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(lg.TimeLayout)
+	}
+
+	switch value := v.Any().(type) {
+	case *slog.Source:
+		return fmt.Sprintf("%s:%d", lg.ShortPath(value.File), value.Line)
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	case encoding.TextMarshaler:
+		if text, err := value.MarshalText(); err == nil {
+			return string(text)
+		}
+	case []byte:
+		return string(value)
+	}
+	if b, err := json.Marshal(v.Any()); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v.Any())
+}
+
+func shortCaller(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return lg.ShortPath(frame.File) + ":" + strconv.Itoa(frame.Line)
+}
+
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+func (h *lineHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	derived := *h
+	derived.attrs = append([]byte(nil), h.attrs...)
+	prefix := groupPrefix(h.groups)
+	for _, attr := range attrs {
+		derived.attrs = h.appendAttr(derived.attrs, h.groups, prefix, attr)
+	}
+	return &derived
+}
+
+func (h *lineHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &derived
+}
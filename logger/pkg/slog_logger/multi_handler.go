@@ -2,11 +2,41 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync"
+	"time"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
 )
 
+// MultiHandlerOptions isolate the handlers of a multi handler from each other.
+type MultiHandlerOptions struct {
+	// Concurrent dispatches every record to the handlers in parallel.
+	Concurrent bool
+
+	// OnError is called with the index and the error of a failing handler.
+	OnError func(index int, err error)
+
+	// MaxFailures consecutive errors disable a handler for RetryAfter
+	// (default 1s), doubled after every failed retry up to MaxRetryAfter
+	// (default 1m). A successful retry enables the handler again. Zero never
+	// disables handlers.
+	MaxFailures   int
+	RetryAfter    time.Duration
+	MaxRetryAfter time.Duration
+
+	// Fallback handles the records a handler failed on or skipped while disabled.
+	Fallback slog.Handler
+}
+
 type multiHandler struct {
 	handlers []slog.Handler
+	// states track the failures of the handlers, they are shared by the
+	// handlers derived with WithAttrs and WithGroup.
+	states   []*lg.SinkFailures
+	fallback slog.Handler
+	opts     MultiHandlerOptions
 }
 
 func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
@@ -16,7 +46,23 @@ func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
 	if len(handlers) == 1 {
 		return handlers[0]
 	}
-	return &multiHandler{handlers: handlers}
+	return NewMultiHandlerWithOptions(MultiHandlerOptions{}, handlers...)
+}
+
+func NewMultiHandlerWithOptions(opts MultiHandlerOptions, handlers ...slog.Handler) slog.Handler {
+	if len(handlers) == 0 {
+		return opts.Fallback
+	}
+	states := make([]*lg.SinkFailures, len(handlers))
+	for i := range states {
+		states[i] = lg.NewSinkFailures(opts.MaxFailures, opts.RetryAfter, opts.MaxRetryAfter)
+	}
+	return &multiHandler{
+		handlers: handlers,
+		states:   states,
+		fallback: opts.Fallback,
+		opts:     opts,
+	}
 }
 
 func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
@@ -29,15 +75,47 @@ func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *multiHandler) Handle(ctx context.Context, r slog.Record) error {
-	var firstErr error
-	for _, handler := range h.handlers {
-		if handler.Enabled(ctx, r.Level) {
-			if err := handler.Handle(ctx, r); err != nil && firstErr == nil {
-				firstErr = err
-			}
+	now := time.Now()
+	errs := make([]error, len(h.handlers))
+	skipped := false
+
+	var wg sync.WaitGroup
+	for i, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if !h.states[i].Enabled(now) {
+			skipped = true
+			continue
+		}
+		if !h.opts.Concurrent {
+			errs[i] = h.handle(ctx, i, r)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, r slog.Record) {
+			defer wg.Done()
+			errs[i] = h.handle(ctx, i, r)
+		}(i, r.Clone())
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if h.fallback != nil && (skipped || err != nil) && h.fallback.Enabled(ctx, r.Level) {
+		if fallbackErr := h.fallback.Handle(ctx, r); fallbackErr != nil {
+			err = errors.Join(err, fallbackErr)
 		}
 	}
-	return firstErr
+	return err
+}
+
+func (h *multiHandler) handle(ctx context.Context, i int, r slog.Record) error {
+	err := h.handlers[i].Handle(ctx, r)
+	h.states[i].Record(err, time.Now())
+	if err != nil && h.opts.OnError != nil {
+		h.opts.OnError(i, err)
+	}
+	return err
 }
 
 func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
@@ -49,7 +127,12 @@ func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	for i, handler := range h.handlers {
 		handlers[i] = handler.WithAttrs(attrs)
 	}
-	return NewMultiHandler(handlers...)
+	derived := *h
+	derived.handlers = handlers
+	if h.fallback != nil {
+		derived.fallback = h.fallback.WithAttrs(attrs)
+	}
+	return &derived
 }
 
 func (h *multiHandler) WithGroup(name string) slog.Handler {
@@ -61,5 +144,10 @@ func (h *multiHandler) WithGroup(name string) slog.Handler {
 	for i, handler := range h.handlers {
 		handlers[i] = handler.WithGroup(name)
 	}
-	return NewMultiHandler(handlers...)
+	derived := *h
+	derived.handlers = handlers
+	if h.fallback != nil {
+		derived.fallback = h.fallback.WithGroup(name)
+	}
+	return &derived
 }
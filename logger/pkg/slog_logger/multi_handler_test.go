@@ -0,0 +1,263 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+)
+
+var errSink = errors.New("sink down")
+
+// recordingHandler records the messages it handles with the keys of its
+// attributes, or fails while failing is set. Handlers derived with
+// WithAttrs share the state of their parent.
+type recordingHandler struct {
+	state *recordingState
+	keys  []string
+}
+
+type recordingState struct {
+	mu       sync.Mutex
+	failing  bool
+	calls    int
+	messages []string
+	// barrier, when set, makes Handle wait for the other handlers sharing it.
+	barrier *sync.WaitGroup
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{state: &recordingState{}}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	if barrier := h.state.barrier; barrier != nil {
+		barrier.Done()
+		waited := make(chan struct{})
+		go func() { barrier.Wait(); close(waited) }()
+		select {
+		case <-waited:
+		case <-time.After(time.Second):
+			return errors.New("handlers did not run concurrently")
+		}
+	}
+
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	h.state.calls++
+	if h.state.failing {
+		return errSink
+	}
+	h.state.messages = append(h.state.messages, strings.Join(append([]string{r.Message}, h.keys...), " "))
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	keys := append([]string(nil), h.keys...)
+	for _, attr := range attrs {
+		keys = append(keys, attr.Key)
+	}
+	return &recordingHandler{state: h.state, keys: keys}
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func (h *recordingHandler) setFailing(failing bool) {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	h.state.failing = failing
+}
+
+func (h *recordingHandler) handled() []string {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return append([]string(nil), h.state.messages...)
+}
+
+func (h *recordingHandler) callCount() int {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return h.state.calls
+}
+
+func handle(t *testing.T, h slog.Handler, msg string) error {
+	t.Helper()
+	return h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0))
+}
+
+func TestMultiHandlerFailures(t *testing.T) {
+	type step struct {
+		fail     bool // the failing handler returns an error
+		handled  bool // the failing handler is called
+		fallback bool // the fallback handles the record
+	}
+	tests := []struct {
+		name        string
+		maxFailures int
+		noFallback  bool
+		steps       []step
+	}{
+		{
+			name:  "never disabled",
+			steps: []step{{true, true, true}, {true, true, true}, {true, true, true}, {false, true, false}},
+		},
+		{
+			name:        "disabled after MaxFailures",
+			maxFailures: 2,
+			steps:       []step{{true, true, true}, {true, true, true}, {true, false, true}, {false, false, true}},
+		},
+		{
+			name:        "success resets failures",
+			maxFailures: 2,
+			steps:       []step{{true, true, true}, {false, true, false}, {true, true, true}, {false, true, false}},
+		},
+		{
+			name:        "disabled without fallback",
+			maxFailures: 1,
+			noFallback:  true,
+			steps:       []step{{true, true, false}, {false, false, false}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			failing, healthy, fallback := newRecordingHandler(), newRecordingHandler(), newRecordingHandler()
+			var errorIndexes []int
+			opts := slg.MultiHandlerOptions{
+				MaxFailures: tc.maxFailures,
+				RetryAfter:  time.Hour,
+				OnError:     func(index int, err error) { errorIndexes = append(errorIndexes, index) },
+				Fallback:    fallback,
+			}
+			if tc.noFallback {
+				opts.Fallback = nil
+			}
+			h := slg.NewMultiHandlerWithOptions(opts, healthy, failing)
+
+			var wantFailing, wantFallback []string
+			var wantIndexes []int
+			for i, s := range tc.steps {
+				msg := string(rune('a' + i))
+				failing.setFailing(s.fail)
+				err := handle(t, h, msg)
+
+				switch {
+				case s.handled && s.fail:
+					require.ErrorIs(t, err, errSink, msg)
+					wantIndexes = append(wantIndexes, 1)
+				case s.handled:
+					require.NoError(t, err, msg)
+					wantFailing = append(wantFailing, msg)
+				default:
+					require.NoError(t, err, msg)
+				}
+				if s.fallback {
+					wantFallback = append(wantFallback, msg)
+				}
+			}
+
+			require.Len(t, healthy.handled(), len(tc.steps))
+			require.Equal(t, wantFailing, failing.handled())
+			require.Equal(t, wantFallback, fallback.handled())
+			require.Equal(t, wantIndexes, errorIndexes)
+		})
+	}
+}
+
+// retryDelay handles records until failing is called again and returns the
+// time from since, the start of the call disabling it, to the start of the
+// call retrying it.
+func retryDelay(t *testing.T, h slog.Handler, failing *recordingHandler, since time.Time) (time.Duration, time.Time) {
+	t.Helper()
+	calls := failing.callCount()
+	for time.Since(since) < time.Second {
+		start := time.Now()
+		_ = handle(t, h, "retry")
+		if failing.callCount() > calls {
+			return start.Sub(since), start
+		}
+		time.Sleep(time.Millisecond)
+	}
+	require.FailNow(t, "the failing handler was not retried")
+	return 0, time.Time{}
+}
+
+func TestMultiHandlerRetryAfter(t *testing.T) {
+	const retryAfter, maxRetryAfter, tolerance = 40 * time.Millisecond, 100 * time.Millisecond, 30 * time.Millisecond
+	failing := newRecordingHandler()
+	failing.setFailing(true)
+	h := slg.NewMultiHandlerWithOptions(slg.MultiHandlerOptions{
+		MaxFailures:   1,
+		RetryAfter:    retryAfter,
+		MaxRetryAfter: maxRetryAfter,
+	}, newRecordingHandler(), failing)
+
+	since := time.Now()
+	require.ErrorIs(t, handle(t, h, "fail"), errSink)
+	for _, want := range []time.Duration{retryAfter, 2 * retryAfter, maxRetryAfter, maxRetryAfter} {
+		var delay time.Duration
+		delay, since = retryDelay(t, h, failing, since)
+		require.GreaterOrEqual(t, delay, want)
+		require.Less(t, delay, want+tolerance)
+	}
+
+	// A successful retry enables the handler and resets the delay.
+	failing.setFailing(false)
+	_, _ = retryDelay(t, h, failing, since)
+	require.NoError(t, handle(t, h, "ok"))
+	require.Equal(t, []string{"retry", "ok"}, failing.handled())
+
+	failing.setFailing(true)
+	since = time.Now()
+	require.ErrorIs(t, handle(t, h, "fail"), errSink)
+	delay, _ := retryDelay(t, h, failing, since)
+	require.GreaterOrEqual(t, delay, retryAfter)
+	require.Less(t, delay, retryAfter+tolerance)
+}
+
+// Handlers derived with WithAttrs and WithGroup share the failures of their
+// parent, and the fallback gets their attributes.
+func TestMultiHandlerWithAttrs(t *testing.T) {
+	failing, healthy, fallback := newRecordingHandler(), newRecordingHandler(), newRecordingHandler()
+	h := slg.NewMultiHandlerWithOptions(slg.MultiHandlerOptions{
+		MaxFailures: 1,
+		RetryAfter:  time.Hour,
+		Fallback:    fallback,
+	}, healthy, failing)
+	derived := h.WithAttrs([]slog.Attr{slog.String("request_id", "1")}).WithGroup("request")
+
+	failing.setFailing(true)
+	require.ErrorIs(t, handle(t, derived, "a"), errSink)
+	failing.setFailing(false)
+	require.NoError(t, handle(t, h, "b"))
+	require.NoError(t, handle(t, derived, "c"))
+
+	require.Equal(t, 1, failing.callCount())
+	require.Equal(t, []string{"a request_id", "b", "c request_id"}, healthy.handled())
+	require.Equal(t, []string{"a request_id", "b", "c request_id"}, fallback.handled())
+}
+
+func TestMultiHandlerConcurrent(t *testing.T) {
+	var barrier sync.WaitGroup
+	handlers := []*recordingHandler{newRecordingHandler(), newRecordingHandler(), newRecordingHandler()}
+	barrier.Add(len(handlers))
+	for _, handler := range handlers {
+		handler.state.barrier = &barrier
+	}
+	handlers[2].setFailing(true)
+
+	h := slg.NewMultiHandlerWithOptions(slg.MultiHandlerOptions{Concurrent: true},
+		handlers[0], handlers[1], handlers[2])
+	require.ErrorIs(t, handle(t, h.WithAttrs([]slog.Attr{slog.Int("n", 1)}), "parallel"), errSink)
+	for _, handler := range handlers[:2] {
+		require.Equal(t, []string{"parallel n"}, handler.handled())
+	}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+type recordGroup struct {
+	name   string
+	fields []lg.Field
+}
+
+// recordHandler passes records to a structured sink as lg.Record. Groups
+// become group fields.
+type recordHandler struct {
+	w      lg.RecordWriter
+	level  slog.Leveler
+	groups []recordGroup
+}
+
+// NewRecordHandler writes the records enabled by level to w, e.g. an
+// OTLP exporter or a syslog writer.
+func NewRecordHandler(w lg.RecordWriter, level slog.Leveler) slog.Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &recordHandler{w: w, level: level, groups: []recordGroup{{}}}
+}
+
+func (h *recordHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	var fields []lg.Field
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttrFields(fields, attr)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		group := h.groups[i]
+		fields = append(group.fields[:len(group.fields):len(group.fields)], fields...)
+		if i > 0 && len(fields) > 0 {
+			fields = []lg.Field{lg.Group(group.name, fields...)}
+		}
+	}
+
+	return h.w.WriteRecord(lg.Record{
+		Time:       r.Time,
+		Severity:   recordSeverity(r.Level),
+		Level:      r.Level.String(),
+		Message:    r.Message,
+		PC:         r.PC,
+		Attributes: fields,
+	})
+}
+
+func (h *recordHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	derived := *h
+	derived.groups = append([]recordGroup(nil), h.groups...)
+	last := &derived.groups[len(derived.groups)-1]
+	last.fields = append([]lg.Field(nil), last.fields...)
+	for _, attr := range attrs {
+		last.fields = appendAttrFields(last.fields, attr)
+	}
+	return &derived
+}
+
+func (h *recordHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.groups = append(h.groups[:len(h.groups):len(h.groups)], recordGroup{name: name})
+	return &derived
+}
+
+// appendAttrFields appends attr as a field, inlining groups without a key
+// and dropping empty attributes and groups like the slog handlers. Stacks
+// are kept as lg.Stack instead of their group value.
+func appendAttrFields(fields []lg.Field, attr slog.Attr) []lg.Field {
+	if stack, ok := attr.Value.Any().(lg.Stack); ok {
+		return append(fields, lg.Any(attr.Key, stack))
+	}
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(fields, lg.Any(attr.Key, attr.Value.Any()))
+	}
+
+	var members []lg.Field
+	for _, member := range attr.Value.Group() {
+		members = appendAttrFields(members, member)
+	}
+	if attr.Key == "" {
+		return append(fields, members...)
+	}
+	if len(members) == 0 {
+		return fields
+	}
+	return append(fields, lg.Group(attr.Key, members...))
+}
+
+// recordSeverity maps slog levels to OpenTelemetry severity numbers, DEBUG,
+// INFO, WARN and ERROR to their first number and levels in between to the
+// following numbers.
+func recordSeverity(level slog.Level) int32 {
+	return int32(min(max(level+lg.SeverityInfo, 1), 24))
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// redactHandler applies lg.Redaction to the attributes of records and loggers.
+type redactHandler struct {
+	handler   slog.Handler
+	redaction *lg.Redaction
+}
+
+func NewRedactHandler(handler slog.Handler, redaction *lg.Redaction) slog.Handler {
+	return &redactHandler{
+		handler:   handler,
+		redaction: redaction,
+	}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	newRecord := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		newRecord.AddAttrs(redactAttr(h.redaction, attr))
+		return true
+	})
+	return h.handler.Handle(ctx, newRecord)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &redactHandler{
+		handler:   h.handler.WithAttrs(redactAttrs(h.redaction, attrs)),
+		redaction: h.redaction,
+	}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &redactHandler{
+		handler:   h.handler.WithGroup(name),
+		redaction: h.redaction,
+	}
+}
+
+func redactAttrs(redaction *lg.Redaction, attrs []slog.Attr) []slog.Attr {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(redaction, attr)
+	}
+	return redacted
+}
+
+func redactAttr(redaction *lg.Redaction, attr slog.Attr) slog.Attr {
+	if attr.Key == lg.StacktraceKey {
+		return attr
+	}
+	if redactor, ok := attr.Value.Any().(lg.Redactor); ok {
+		attr.Value = slog.AnyValue(redactor.Redact())
+	}
+	attr.Value = attr.Value.Resolve()
+
+	if strategy, ok := redaction.MatchKey(attr.Key); ok {
+		attr.Value = slog.StringValue(lg.Conceal(strategy, attr.Value.String()))
+		return attr
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		attr.Value = slog.GroupValue(redactAttrs(redaction, attr.Value.Group())...)
+	case slog.KindString:
+		attr.Value = slog.StringValue(redaction.String(attr.Value.String()))
+	case slog.KindAny:
+		if redacted, ok := redaction.Render(attr.Value.Any()); ok {
+			attr.Value = slog.StringValue(redacted)
+		}
+	}
+	return attr
+}
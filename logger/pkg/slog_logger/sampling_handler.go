@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// samplingHandler drops records rejected by lg.Sampler and logs the number
+// of dropped records through the wrapped handler.
+type samplingHandler struct {
+	handler slog.Handler
+	sampler *lg.Sampler
+}
+
+// NewSamplingHandler starts the dropped records reporting of sampler, stop it
+// with sampler.Close. Reports are checked against the level of every sink
+// like other records.
+func NewSamplingHandler(handler slog.Handler, sampler *lg.Sampler) slog.Handler {
+	sampler.Start(func(fields []lg.Field) {
+		ctx := context.Background()
+		if !handler.Enabled(ctx, slog.LevelWarn) {
+			return
+		}
+		r := slog.NewRecord(time.Now(), slog.LevelWarn, lg.MsgRecordsDropped, 0)
+		r.AddAttrs(fieldsToAttrs(fields)...)
+		_ = handler.Handle(ctx, r)
+	})
+	return &samplingHandler{
+		handler: handler,
+		sampler: sampler,
+	}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.Sample(r.Level.String(), r.Message) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &samplingHandler{
+		handler: h.handler.WithAttrs(attrs),
+		sampler: h.sampler,
+	}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &samplingHandler{
+		handler: h.handler.WithGroup(name),
+		sampler: h.sampler,
+	}
+}
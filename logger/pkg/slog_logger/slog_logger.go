@@ -4,198 +4,405 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"os"
-	"path/filepath"
-	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	olg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/otlp_logger"
 )
 
-const (
-	unknownFunctionName = "unknown function name"
-	funcNameSeparator   = ": "
-)
+const funcNameSeparator = ": "
 
-type SQLErrorType int
+type SQLErrorType = lg.SQLErrorType
 
 const (
-	SQLSelect SQLErrorType = iota
-	SQLInsert
-	SQLUpdate
-	SQLDelete
+	SQLSelect     = lg.SQLSelect
+	SQLInsert     = lg.SQLInsert
+	SQLUpdate     = lg.SQLUpdate
+	SQLDelete     = lg.SQLDelete
+	SQLBegin      = lg.SQLBegin
+	SQLCommit     = lg.SQLCommit
+	SQLRollback   = lg.SQLRollback
+	SQLDDL        = lg.SQLDDL
+	SQLMigration  = lg.SQLMigration
+	SQLUpsert     = lg.SQLUpsert
+	SQLConnection = lg.SQLConnection
 )
 
 type Logger struct {
-	slogLog      *slog.Logger
-	functionName string
-	ctx          context.Context
+	slogLog       *slog.Logger
+	level         slog.Leveler
+	functionName  string
+	ctx           context.Context
+	ctxAttrs      []slog.Attr
+	start         time.Time
+	slowThreshold time.Duration
+	withStack     bool
+	callerSkip    int
+	// origin is the program counter the logger was created at, see lg.CallerPC.
+	origin uintptr
 }
 
 type LoggerFactory struct {
-	slogLog *slog.Logger
-	config  Config
-	file    *os.File
+	slogLog     *slog.Logger
+	level       *slog.LevelVar
+	rules       lg.LevelRules
+	ruleLevels  []slog.Level
+	config      Config
+	outputs     *lg.Outputs
+	sampler     *lg.Sampler
+	stopWatcher func()
 }
 
-type Config struct {
-	ServiceName string
-	Version     string
-	LogLevel    string
-	OutputPath  string
-}
+type Config = lg.Config
 
 func NewLoggerFactory(cfg Config) (*LoggerFactory, error) {
-	slogLog, file, err := newSlogLogger(cfg)
+	logLevel, err := parseLogLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	level := new(slog.LevelVar)
+	level.Set(logLevel)
+
+	rules, err := lg.ParseLevelRules(cfg.LevelRules)
 	if err != nil {
 		return nil, err
 	}
+	ruleLevels := make([]slog.Level, len(rules))
+	for i, rule := range rules {
+		if ruleLevels[i], err = lookupLogLevel(rule.Level); err != nil {
+			return nil, err
+		}
+	}
 
-	return &LoggerFactory{
-		slogLog: slogLog,
-		config:  cfg,
-		file:    file,
-	}, nil
+	sampler, err := lg.NewSampler(cfg.Sampling)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := lg.NewOutputs(cfg)
+	slogLog, err := newSlogLogger(cfg, &floorLevel{level: level, rules: ruleLevels}, sampler, outputs)
+	if err != nil {
+		_ = outputs.Close()
+		return nil, err
+	}
+
+	factory := &LoggerFactory{
+		slogLog:    slogLog,
+		level:      level,
+		rules:      rules,
+		ruleLevels: ruleLevels,
+		config:     cfg,
+		outputs:    outputs,
+		sampler:    sampler,
+	}
+
+	if cfg.ReopenOnSIGHUP && outputs.HasFiles() {
+		factory.stopWatcher = lg.ReopenOnSignal(factory, func(err error) {
+			slogLog.Error(lg.MsgReopenFailed, attrsToArgs(fieldsToAttrs(lg.EncodeError(err)))...)
+		})
+	}
+
+	return factory, nil
 }
 
 func (f *LoggerFactory) GetLogger(ctx context.Context, attrs ...slog.Attr) *Logger {
+	pc := f.callerPC()
+	return f.newLogger(ctx, pc, lg.FunctionName(pc), attrs)
+}
+
+// callerPC returns the program counter of the code calling a public factory
+// method, it must be called directly by that method.
+func (f *LoggerFactory) callerPC() uintptr {
+	return lg.CallerPC(2+f.config.CallerSkip, 0)
+}
+
+func (f *LoggerFactory) newLogger(ctx context.Context, pc uintptr, fullFunctionName string, attrs []slog.Attr) *Logger {
 	msg := lg.MsgStart
 	if len(attrs) > 0 {
 		msg = lg.MsgStartWithParams
 	}
 
-	functionName := getFunctionName(2, true)
+	var level slog.Leveler = f.level
+	if idx := f.rules.Match(fullFunctionName); idx != -1 {
+		level = f.ruleLevels[idx]
+	}
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	logger := &Logger{
-		slogLog:      f.slogLog,
-		functionName: functionName,
-		ctx:          ctx,
+		slogLog:       f.slogLog,
+		level:         level,
+		functionName:  shortenFunctionName(fullFunctionName),
+		ctx:           ctx,
+		ctxAttrs:      fieldsToAttrs(lg.ExtractContextFields(ctx, f.config.ContextExtractors)),
+		start:         time.Now(),
+		slowThreshold: f.config.SlowCallThreshold,
+		withStack:     f.config.StacktraceOnError,
+		callerSkip:    f.config.CallerSkip,
+		origin:        pc,
 	}
+	logger.logMessage(pc, slog.LevelInfo, msg, attrs)
+	return logger
+}
 
-	if len(attrs) > 0 {
-		logger.slogLog.InfoContext(ctx, createMessageWithFuncName(functionName, msg), attrsToArgs(attrs)...)
-	} else {
-		logger.slogLog.InfoContext(ctx, createMessageWithFuncName(functionName, msg))
+// Level returns the current minimal level of the factory loggers.
+func (f *LoggerFactory) Level() string {
+	return strings.ToLower(f.level.Level().String())
+}
+
+// SetLevel changes the minimal level of all loggers created by the factory,
+// except those matched by a level rule, which keep the level of the rule.
+func (f *LoggerFactory) SetLevel(level string) error {
+	logLevel, err := lookupLogLevel(level)
+	if err != nil {
+		return err
 	}
+	f.level.Set(logLevel)
+	return nil
+}
 
-	return logger
+// Reopen reopens the log files, see lg.RotatingFile.Reopen.
+func (f *LoggerFactory) Reopen() error {
+	return f.outputs.Reopen()
+}
+
+// AsyncStats returns the queue metrics of the sinks when Config.Async is set.
+func (f *LoggerFactory) AsyncStats() []lg.AsyncStats {
+	return f.outputs.AsyncStats()
 }
 
 func (f *LoggerFactory) Close() error {
-	if f.file != nil {
-		return f.file.Close()
+	if f.stopWatcher != nil {
+		f.stopWatcher()
 	}
-	return nil
+	f.sampler.Close()
+	return f.outputs.Close()
 }
 
 func (l *Logger) WithFields(attrs ...slog.Attr) *Logger {
-	return &Logger{
-		slogLog:      l.slogLog.With(attrsToArgs(attrs)...),
-		functionName: l.functionName,
-		ctx:          l.ctx,
-	}
+	derived := *l
+	derived.slogLog = l.slogLog.With(attrsToArgs(attrs)...)
+	derived.ctxAttrs = withoutKeys(l.ctxAttrs, attrs)
+	return &derived
+}
+
+// WithCallerSkip returns a logger attributing records to the code skip
+// frames above its usual caller, for libraries wrapping the logger.
+func (l *Logger) WithCallerSkip(skip int) *Logger {
+	derived := *l
+	derived.callerSkip += skip
+	return &derived
 }
 
 func (l *Logger) Debug(msg string, attrs ...slog.Attr) {
-	if l.slogLog.Enabled(l.ctx, slog.LevelDebug) {
-		l.slogLog.DebugContext(l.ctx, createMessageWithFuncName(l.functionName, msg), attrsToArgs(attrs)...)
-	}
+	l.logMessage(l.callerPC(), slog.LevelDebug, msg, attrs)
 }
 
 func (l *Logger) Info(msg string, attrs ...slog.Attr) {
-	if l.slogLog.Enabled(l.ctx, slog.LevelInfo) {
-		l.slogLog.InfoContext(l.ctx, createMessageWithFuncName(l.functionName, msg), attrsToArgs(attrs)...)
-	}
+	l.logMessage(l.callerPC(), slog.LevelInfo, msg, attrs)
 }
 
 func (l *Logger) Warning(msg string, attrs ...slog.Attr) {
-	if l.slogLog.Enabled(l.ctx, slog.LevelWarn) {
-		l.slogLog.WarnContext(l.ctx, createMessageWithFuncName(l.functionName, msg), attrsToArgs(attrs)...)
-	}
+	l.logMessage(l.callerPC(), slog.LevelWarn, msg, attrs)
 }
 
 func (l *Logger) Error(msg string, attrs ...slog.Attr) {
-	if l.slogLog.Enabled(l.ctx, slog.LevelError) {
-		l.slogLog.ErrorContext(l.ctx, createMessageWithFuncName(l.functionName, msg), attrsToArgs(attrs)...)
-	}
+	l.logMessage(l.callerPC(), slog.LevelError, msg, attrs)
 }
 
 func (l *Logger) ErrorIn(funcName string, err error, attrs ...slog.Attr) {
-	msg := fmt.Sprintf(lg.MsgCompletesWithError, funcName)
-	allAttrs := append(attrs, slog.String("error", err.Error()))
-	l.slogLog.ErrorContext(l.ctx, createMessageWithFuncName(l.functionName, msg), attrsToArgs(allAttrs)...)
+	l.errorIn(l.callerPC(), funcName, err, attrs)
 }
 
 func (l *Logger) ErrorSQL(operation SQLErrorType, table string, err error, attrs ...slog.Attr) {
-	var msg string
-	switch operation {
-	case SQLSelect:
-		msg = fmt.Sprintf(lg.MsgSQLSelectWithError, table)
-	case SQLInsert:
-		msg = fmt.Sprintf(lg.MsgSQLInsertWithError, table)
-	case SQLUpdate:
-		msg = fmt.Sprintf(lg.MsgSQLUpdateWithError, table)
-	case SQLDelete:
-		msg = fmt.Sprintf(lg.MsgSQLDeleteWithError, table)
-	default:
-		msg = fmt.Sprintf("SQL operation error on table %s", table)
-	}
-
-	allAttrs := append(attrs, slog.String("error", err.Error()), slog.String("table", table))
-	l.slogLog.ErrorContext(l.ctx, createMessageWithFuncName(l.functionName, msg), attrsToArgs(allAttrs)...)
+	l.errorSQL(l.callerPC(), operation, table, err, attrs)
 }
 
 func (l *Logger) ErrorSQLSelect(table string, err error, attrs ...slog.Attr) {
-	l.ErrorSQL(SQLSelect, table, err, attrs...)
+	l.errorSQL(l.callerPC(), SQLSelect, table, err, attrs)
 }
 
 func (l *Logger) ErrorSQLInsert(table string, err error, attrs ...slog.Attr) {
-	l.ErrorSQL(SQLInsert, table, err, attrs...)
+	l.errorSQL(l.callerPC(), SQLInsert, table, err, attrs)
 }
 
 func (l *Logger) ErrorSQLUpdate(table string, err error, attrs ...slog.Attr) {
-	l.ErrorSQL(SQLUpdate, table, err, attrs...)
+	l.errorSQL(l.callerPC(), SQLUpdate, table, err, attrs)
 }
 
 func (l *Logger) ErrorSQLDelete(table string, err error, attrs ...slog.Attr) {
-	l.ErrorSQL(SQLDelete, table, err, attrs...)
+	l.errorSQL(l.callerPC(), SQLDelete, table, err, attrs)
+}
+
+func (l *Logger) ErrorSQLBegin(err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLBegin, "", err, attrs)
+}
+
+func (l *Logger) ErrorSQLCommit(err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLCommit, "", err, attrs)
+}
+
+func (l *Logger) ErrorSQLRollback(err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLRollback, "", err, attrs)
+}
+
+func (l *Logger) ErrorSQLDDL(table string, err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLDDL, table, err, attrs)
+}
+
+func (l *Logger) ErrorSQLMigration(migration string, err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLMigration, migration, err, attrs)
+}
+
+func (l *Logger) ErrorSQLUpsert(table string, err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLUpsert, table, err, attrs)
+}
+
+func (l *Logger) ErrorSQLConnection(err error, attrs ...slog.Attr) {
+	l.errorSQL(l.callerPC(), SQLConnection, "", err, attrs)
 }
 
 func (l *Logger) End() {
-	if err := recover(); err != nil {
-		l.slogLog.ErrorContext(l.ctx, lg.MsgPanicWasCatched,
-			slog.Any("error", err),
-			slog.String("function", l.functionName))
-		l.slogLog.InfoContext(l.ctx, createMessageWithFuncName(l.functionName, lg.MsgEnd))
-		panic(err)
-	}
-	l.slogLog.InfoContext(l.ctx, createMessageWithFuncName(l.functionName, lg.MsgEnd))
+	l.end(l.callerPC(), recover(), nil)
 }
 
-func createMessageWithFuncName(funcName, msg string) string {
-	if funcName == "" {
-		return msg
+// EndWithError is End for functions with a named error result, use it as
+// defer logger.EndWithError(&err). A non-nil error is logged at Error level
+// with its wrapped chain.
+func (l *Logger) EndWithError(errPtr *error) {
+	l.end(l.callerPC(), recover(), errPtr)
+}
+
+func (l *Logger) errorIn(pc uintptr, funcName string, err error, attrs []slog.Attr) {
+	msg := fmt.Sprintf(lg.MsgCompletesWithError, funcName)
+	l.logMessage(pc, slog.LevelError, msg, append(attrs, fieldsToAttrs(lg.EncodeError(err))...))
+}
+
+func (l *Logger) errorSQL(pc uintptr, operation SQLErrorType, table string, err error, attrs []slog.Attr) {
+	allAttrs := append(attrs, fieldsToAttrs(lg.EncodeError(err))...)
+	allAttrs = append(allAttrs, fieldsToAttrs(lg.SQLFields(operation, table))...)
+	l.logMessage(pc, slog.LevelError, lg.SQLErrorMessage(operation, table), allAttrs)
+}
+
+func (l *Logger) end(pc uintptr, panicValue any, errPtr *error) {
+	if panicValue != nil {
+		if l.enabled(slog.LevelError) {
+			l.log(pc, slog.LevelError, lg.MsgPanicWasCatched, []slog.Attr{
+				slog.Any("error", panicValue),
+				slog.String("panic_type", fmt.Sprintf("%T", panicValue)),
+				slog.String("function", l.functionName),
+				slog.Any(lg.StacktraceKey, lg.CaptureStackFrom(pc)),
+			})
+		}
+		l.logEnd(pc, nil)
+		panic(panicValue)
 	}
-	return funcName + funcNameSeparator + msg
+
+	var err error
+	if errPtr != nil {
+		err = *errPtr
+	}
+	l.logEnd(pc, err)
 }
 
-func getFunctionName(skippedStackFrames int, shortFunctionName bool) string {
-	pc, _, _, ok := runtime.Caller(skippedStackFrames + 1)
-	if !ok {
-		return unknownFunctionName
+func (l *Logger) logEnd(pc uintptr, err error) {
+	duration := time.Since(l.start)
+
+	if err != nil {
+		attrs := append(fieldsToAttrs(lg.EncodeError(err)), slog.Duration(lg.DurationKey, duration))
+		l.logMessage(pc, slog.LevelError, lg.MsgEndWithError, attrs)
+		return
 	}
 
-	funcName := runtime.FuncForPC(pc).Name()
+	if l.slowThreshold > 0 && duration > l.slowThreshold {
+		l.logMessage(pc, slog.LevelWarn, lg.MsgEnd, []slog.Attr{
+			slog.Duration(lg.DurationKey, duration),
+			slog.Duration(lg.SlowThresholdKey, l.slowThreshold),
+		})
+		return
+	}
 
-	if shortFunctionName {
-		if idx := strings.LastIndex(funcName, "/"); idx != -1 {
-			funcName = funcName[idx+1:]
+	l.logMessage(pc, slog.LevelInfo, lg.MsgEnd, []slog.Attr{slog.Duration(lg.DurationKey, duration)})
+}
+
+// callerPC returns the program counter of the code calling a public method,
+// it must be called directly by that method.
+func (l *Logger) callerPC() uintptr {
+	return lg.CallerPC(2+l.callerSkip, l.origin)
+}
+
+// logMessage logs msg prefixed with the function name when level is enabled,
+// adding the stack to Error level records without one when StacktraceOnError
+// is set.
+func (l *Logger) logMessage(pc uintptr, level slog.Level, msg string, attrs []slog.Attr) {
+	if !l.enabled(level) {
+		return
+	}
+	if l.withStack && level >= slog.LevelError && !hasStack(attrs) {
+		attrs = append(attrs, slog.Any(lg.StacktraceKey, lg.CaptureStackFrom(pc)))
+	}
+	l.log(pc, level, createMessageWithFuncName(l.functionName, msg), attrs)
+}
+
+func hasStack(attrs []slog.Attr) bool {
+	for _, attr := range attrs {
+		if attr.Key == lg.StacktraceKey {
+			return true
 		}
-		if idx := strings.LastIndex(funcName, "."); idx != -1 {
-			funcName = funcName[idx+1:]
+	}
+	return false
+}
+
+// log writes a record attributed to pc, the caller captured at the public
+// method, instead of the logger internals slog.Logger would report.
+func (l *Logger) log(pc uintptr, level slog.Level, msg string, attrs []slog.Attr) {
+	r := slog.NewRecord(time.Now(), level, msg, pc)
+	r.AddAttrs(l.withContext(attrs)...)
+	_ = l.slogLog.Handler().Handle(l.ctx, r)
+}
+
+// withContext appends context attributes not overridden by attrs.
+func (l *Logger) withContext(attrs []slog.Attr) []slog.Attr {
+	if len(l.ctxAttrs) == 0 {
+		return attrs
+	}
+	return slices.Concat(attrs, withoutKeys(l.ctxAttrs, attrs))
+}
+
+func withoutKeys(attrs []slog.Attr, override []slog.Attr) []slog.Attr {
+	if len(attrs) == 0 || len(override) == 0 {
+		return attrs
+	}
+	kept := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if !slices.ContainsFunc(override, func(o slog.Attr) bool { return o.Key == attr.Key }) {
+			kept = append(kept, attr)
 		}
 	}
+	return kept
+}
+
+func (l *Logger) enabled(level slog.Level) bool {
+	return level >= l.level.Level() && l.slogLog.Enabled(l.ctx, level)
+}
+
+func createMessageWithFuncName(funcName, msg string) string {
+	if funcName == "" {
+		return msg
+	}
+	return funcName + funcNameSeparator + msg
+}
 
+func shortenFunctionName(funcName string) string {
+	if idx := strings.LastIndex(funcName, "/"); idx != -1 {
+		funcName = funcName[idx+1:]
+	}
+	if idx := strings.LastIndex(funcName, "."); idx != -1 {
+		funcName = funcName[idx+1:]
+	}
 	return funcName
 }
 
@@ -210,63 +417,193 @@ func attrsToArgs(attrs []slog.Attr) []any {
 	return args
 }
 
-func parseLogLevel(level string) slog.Level {
+// parseLogLevel parses Config.LogLevel, debug when empty.
+func parseLogLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return slog.LevelDebug, nil
+	}
+	return lookupLogLevel(level)
+}
+
+// lookupLogLevel parses the levels of SetLevel, sinks and rules, which must
+// not be empty.
+func lookupLogLevel(level string) (slog.Level, error) {
 	switch strings.ToUpper(level) {
+	case "":
+		return slog.LevelDebug, lg.ErrEmptyLevel
 	case "DEBUG":
-		return slog.LevelDebug
+		return slog.LevelDebug, nil
 	case "INFO":
-		return slog.LevelInfo
+		return slog.LevelInfo, nil
 	case "WARN", "WARNING":
-		return slog.LevelWarn
+		return slog.LevelWarn, nil
 	case "ERROR":
-		return slog.LevelError
+		return slog.LevelError, nil
 	default:
-		return slog.LevelDebug
+		return slog.LevelDebug, fmt.Errorf("unknown log level: %s", level)
 	}
 }
 
-func sourceKeyReplaceAttr(groups []string, a slog.Attr) slog.Attr {
-	if a.Key == slog.SourceKey {
+// schemaReplaceAttr renames the built-in attributes to the keys of the
+// shared record schema, see lg.TimeKey.
+func schemaReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		if t, ok := a.Value.Any().(time.Time); ok {
+			return slog.String(lg.TimeKey, t.Format(lg.TimeLayout))
+		}
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String(lg.LevelKey, strings.ToLower(level.String()))
+		}
+	case slog.SourceKey:
 		if source, ok := a.Value.Any().(*slog.Source); ok {
-			filename := filepath.Base(source.File)
-			a.Value = slog.StringValue(fmt.Sprintf("%s:%d", filename, source.Line))
+			return slog.String(lg.CallerKey, fmt.Sprintf("%s:%d", lg.ShortPath(source.File), source.Line))
 		}
+	case slog.MessageKey:
+		a.Key = lg.MessageKey
 	}
 	return a
 }
 
-func newSlogLogger(cfg Config) (*slog.Logger, *os.File, error) {
-	logLevel := parseLogLevel(cfg.LogLevel)
+// jsonReplaceAttr also writes durations as milliseconds like the zap backend.
+func jsonReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindDuration {
+		a.Value = slog.Float64Value(float64(a.Value.Duration()) / float64(time.Millisecond))
+	}
+	return schemaReplaceAttr(groups, a)
+}
 
-	var file *os.File
-	var handlers []slog.Handler
+func newSlogLogger(cfg Config, logLevel slog.Leveler, sampler *lg.Sampler, outputs *lg.Outputs) (*slog.Logger, error) {
+	redaction, err := lg.NewRedaction(cfg.RedactRules)
+	if err != nil {
+		return nil, err
+	}
 
-	consoleHandler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level:       logLevel,
-		AddSource:   true,
-		ReplaceAttr: sourceKeyReplaceAttr,
-	})
-	handlers = append(handlers, NewCallerHandler(consoleHandler, 4))
+	sinks, err := cfg.SinkConfigs()
+	if err != nil {
+		return nil, err
+	}
 
-	if cfg.OutputPath != "" {
-		var err error
-		file, err = os.OpenFile(cfg.OutputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	handlers := make([]slog.Handler, 0, len(sinks))
+	for _, sink := range sinks {
+		handler, err := newSinkHandler(cfg, sink, logLevel, redaction, outputs)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+			return nil, err
 		}
-
-		fileHandler := slog.NewJSONHandler(file, &slog.HandlerOptions{
-			Level:       logLevel,
-			AddSource:   true,
-			ReplaceAttr: sourceKeyReplaceAttr,
-		})
-		handlers = append(handlers, NewCallerHandler(fileHandler, 4))
+		handlers = append(handlers, handler)
 	}
 
-	handler := NewMultiHandler(handlers...)
+	var handler slog.Handler
+	if cfg.Failover == nil {
+		handler = NewMultiHandler(handlers...)
+	} else if handler, err = newFailoverHandler(cfg, sinks, handlers, logLevel, redaction, outputs); err != nil {
+		return nil, err
+	}
 	if handler == nil {
-		return slog.Default(), file, nil
+		return slog.Default(), nil
+	}
+	if sampler != nil {
+		handler = NewSamplingHandler(handler, sampler)
+	}
+
+	return slog.New(handler), nil
+}
+
+// newFailoverHandler isolates the sink handlers with the options of cfg.Failover.
+func newFailoverHandler(cfg Config, sinks []lg.SinkConfig, handlers []slog.Handler, logLevel slog.Leveler, redaction *lg.Redaction, outputs *lg.Outputs) (slog.Handler, error) {
+	failover := cfg.Failover
+	opts := MultiHandlerOptions{
+		Concurrent:    failover.Concurrent,
+		MaxFailures:   failover.MaxFailures,
+		RetryAfter:    failover.RetryAfter,
+		MaxRetryAfter: failover.MaxRetryAfter,
+	}
+	if onError := failover.OnError; onError != nil {
+		opts.OnError = func(index int, err error) { onError(sinks[index].Destination, err) }
+	}
+
+	fallback, err := cfg.FallbackConfig()
+	if err != nil {
+		return nil, err
+	}
+	if fallback != nil {
+		if opts.Fallback, err = newSinkHandler(cfg, *fallback, logLevel, redaction, outputs); err != nil {
+			return nil, err
+		}
+	}
+	return NewMultiHandlerWithOptions(opts, handlers...), nil
+}
+
+func newSinkHandler(cfg Config, sink lg.SinkConfig, logLevel slog.Leveler, redaction *lg.Redaction, outputs *lg.Outputs) (slog.Handler, error) {
+	if sink.Level != "" {
+		sinkMin, err := lookupLogLevel(sink.Level)
+		if err != nil {
+			return nil, err
+		}
+		logLevel = &sinkLevel{level: logLevel, min: sinkMin}
+	}
+
+	filter, err := lg.NewSinkFilter(sink.Include, sink.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var handler slog.Handler
+	if sink.IsStructured() {
+		w, err := openRecordWriter(cfg, sink, outputs)
+		if err != nil {
+			return nil, err
+		}
+		handler = NewRecordHandler(w, logLevel)
+	} else if handler, err = newFormatHandler(cfg, sink, logLevel, outputs); err != nil {
+		return nil, err
+	}
+
+	return NewFilterHandler(NewRedactHandler(handler, redaction), filter), nil
+}
+
+func openRecordWriter(cfg Config, sink lg.SinkConfig, outputs *lg.Outputs) (lg.RecordWriter, error) {
+	if !sink.IsOTLP() {
+		return outputs.OpenRecordWriter(sink)
+	}
+	exporter, err := olg.NewExporter(*sink.OTLP, cfg.ServiceName, cfg.Version)
+	if err != nil {
+		return nil, err
+	}
+	outputs.Track(exporter)
+	return exporter, nil
+}
+
+func newFormatHandler(cfg Config, sink lg.SinkConfig, logLevel slog.Leveler, outputs *lg.Outputs) (slog.Handler, error) {
+	w, err := outputs.Open(sink.Destination)
+	if err != nil {
+		return nil, err
 	}
 
-	return slog.New(handler), file, nil
+	serviceAttrs := fieldsToAttrs(lg.ServiceFields(cfg))
+	switch sink.Format {
+	case lg.FormatJSON:
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level:       logLevel,
+			AddSource:   true,
+			ReplaceAttr: jsonReplaceAttr,
+		}).WithAttrs(serviceAttrs), nil
+	case lg.FormatLogfmt:
+		return NewLogfmtHandler(w, &slog.HandlerOptions{
+			Level:       logLevel,
+			AddSource:   true,
+			ReplaceAttr: schemaReplaceAttr,
+		}).WithAttrs(serviceAttrs), nil
+	default:
+		return NewConsoleHandler(w, &ConsoleHandlerOptions{
+			Level:        logLevel,
+			AddSource:    true,
+			Color:        outputs.Colored(sink),
+			CallerPrefix: lg.CallerPrefix(cfg.ServiceName, cfg.Version),
+		}), nil
+	}
 }
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const stackIndent = "\t"
+
+// stackHandler writes lg.Stack attributes as indented multiline text below
+// the record produced by a line-oriented handler such as slog.TextHandler.
+type stackHandler struct {
+	handler slog.Handler
+	w       io.Writer
+	mu      *sync.Mutex
+}
+
+// NewStackHandler wraps handler, which must write to w, and moves stacktrace
+// attributes out of the record line into a readable block after it.
+func NewStackHandler(handler slog.Handler, w io.Writer) slog.Handler {
+	return &stackHandler{
+		handler: handler,
+		w:       w,
+		mu:      new(sync.Mutex),
+	}
+}
+
+func (h *stackHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *stackHandler) Handle(ctx context.Context, r slog.Record) error {
+	var stack lg.Stack
+	newRecord := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		if s, ok := attr.Value.Any().(lg.Stack); ok && attr.Key == lg.StacktraceKey {
+			stack = s
+			return true
+		}
+		newRecord.AddAttrs(attr)
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if stack == nil {
+		return h.handler.Handle(ctx, r)
+	}
+	if err := h.handler.Handle(ctx, newRecord); err != nil {
+		return err
+	}
+	_, err := io.WriteString(h.w, indentLines(stack.String()))
+	return err
+}
+
+func (h *stackHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &stackHandler{
+		handler: h.handler.WithAttrs(attrs),
+		w:       h.w,
+		mu:      h.mu,
+	}
+}
+
+func (h *stackHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &stackHandler{
+		handler: h.handler.WithGroup(name),
+		w:       h.w,
+		mu:      h.mu,
+	}
+}
+
+func indentLines(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		if line != "" {
+			b.WriteString(stackIndent)
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
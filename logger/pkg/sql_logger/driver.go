@@ -0,0 +1,399 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+const (
+	QueryKey = "sql.query"
+	ArgsKey  = "sql.args"
+	TableKey = lg.TableKey
+
+	maskedArg = "***"
+)
+
+// Options configure the statements logged by the driver wrapper.
+type Options struct {
+	// SlowThreshold logs successful statements slower than it at Warning
+	// level. Zero disables slow statement logging.
+	SlowThreshold time.Duration
+
+	// LogArgs adds the statement arguments to the records, passed through
+	// RedactArg when it is set, see MaskArg.
+	LogArgs   bool
+	RedactArg func(arg driver.NamedValue) any
+}
+
+// MaskArg is a RedactArg hiding every argument value.
+func MaskArg(driver.NamedValue) any {
+	return maskedArg
+}
+
+// Open opens a database like sql.Open with the registered driver wrapped by Wrap.
+func Open(driverName, dataSourceName string, logger lg.Logger, opts Options) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	connector, err := Wrap(d, logger, opts).(driver.DriverContext).OpenConnector(dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// Wrap returns a driver logging failed statements through Logger.ErrorSQL and
+// slow statements with their duration. The table and operation are taken
+// from the statement text, records get the fields bound to the query context.
+func Wrap(d driver.Driver, logger lg.Logger, opts Options) driver.Driver {
+	return &wrappedDriver{driver: d, logger: &statementLogger{logger: logger, opts: opts}}
+}
+
+// WrapConnector is Wrap for drivers providing a driver.Connector.
+func WrapConnector(c driver.Connector, logger lg.Logger, opts Options) driver.Connector {
+	l := &statementLogger{logger: logger, opts: opts}
+	return &wrappedConnector{
+		connector: c,
+		driver:    &wrappedDriver{driver: c.Driver(), logger: l},
+		logger:    l,
+	}
+}
+
+type statementLogger struct {
+	logger lg.Logger
+	opts   Options
+}
+
+func (l *statementLogger) log(ctx context.Context, query string, args []driver.NamedValue, start time.Time, err error) {
+	duration := time.Since(start)
+	slow := l.opts.SlowThreshold > 0 && duration > l.opts.SlowThreshold
+	if errors.Is(err, driver.ErrSkip) || (err == nil && !slow) {
+		return
+	}
+
+	logger := l.logger.WithFields(lg.ExtractContextFields(ctx, nil)...)
+	fields := []lg.Field{lg.String(QueryKey, query), lg.Duration(lg.DurationKey, duration)}
+	if l.opts.LogArgs && len(args) > 0 {
+		fields = append(fields, l.argsField(args))
+	}
+
+	st := classify(query)
+	if err != nil {
+		if st.known {
+			logger.ErrorSQL(st.operation, st.table, err, fields...)
+		} else {
+			logger.ErrorIn(st.verb, err, fields...)
+		}
+		return
+	}
+
+	if st.table != "" {
+		fields = append(fields, lg.String(TableKey, st.table))
+	}
+	logger.Warning(lg.MsgSQLSlowStatement, append(fields, lg.Duration(lg.SlowThresholdKey, l.opts.SlowThreshold))...)
+}
+
+// logOperation logs the failure of an operation without statement text,
+// such as Commit or Connect.
+func (l *statementLogger) logOperation(ctx context.Context, operation lg.SQLErrorType, err error) {
+	if err == nil || errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	l.logger.WithFields(lg.ExtractContextFields(ctx, nil)...).ErrorSQL(operation, "", err)
+}
+
+func (l *statementLogger) argsField(args []driver.NamedValue) lg.Field {
+	fields := make([]lg.Field, 0, len(args))
+	for _, arg := range args {
+		key := arg.Name
+		if key == "" {
+			key = strconv.Itoa(arg.Ordinal)
+		}
+		value := arg.Value
+		if l.opts.RedactArg != nil {
+			value = l.opts.RedactArg(arg)
+		}
+		fields = append(fields, lg.Any(key, value))
+	}
+	return lg.Group(ArgsKey, fields...)
+}
+
+type wrappedDriver struct {
+	driver driver.Driver
+	logger *statementLogger
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		d.logger.logOperation(context.Background(), lg.SQLConnection, err)
+		return nil, err
+	}
+	return &wrappedConn{conn: conn, logger: d.logger}, nil
+}
+
+func (d *wrappedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &wrappedConnector{connector: connector, driver: d, logger: d.logger}, nil
+	}
+	return &dsnConnector{name: name, driver: d}, nil
+}
+
+type wrappedConnector struct {
+	connector driver.Connector
+	driver    *wrappedDriver
+	logger    *statementLogger
+}
+
+func (c *wrappedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		c.logger.logOperation(ctx, lg.SQLConnection, err)
+		return nil, err
+	}
+	return &wrappedConn{conn: conn, logger: c.logger}, nil
+}
+
+func (c *wrappedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector opens connections of drivers without driver.DriverContext.
+type dsnConnector struct {
+	name   string
+	driver *wrappedDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type wrappedConn struct {
+	conn   driver.Conn
+	logger *statementLogger
+}
+
+func (c *wrappedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+	var stmt driver.Stmt
+	var err error
+	if cp, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = cp.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		c.logger.log(ctx, query, nil, start, err)
+		return nil, err
+	}
+	return &wrappedStmt{stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *wrappedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *wrappedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	var err error
+	if cb, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = cb.BeginTx(ctx, opts)
+	} else {
+		if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+			return nil, errors.New("sql: driver does not support non-default transaction options")
+		}
+		tx, err = c.conn.Begin() //nolint:staticcheck // fallback for drivers without ConnBeginTx
+	}
+	if err != nil {
+		c.logger.logOperation(ctx, lg.SQLBegin, err)
+		return nil, err
+	}
+	return &wrappedTx{tx: tx, ctx: ctx, logger: c.logger}, nil
+}
+
+func (c *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.logger.log(ctx, query, args, start, err)
+	return result, err
+}
+
+func (c *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.logger.log(ctx, query, args, start, err)
+	return rows, err
+}
+
+func (c *wrappedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *wrappedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *wrappedConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *wrappedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type wrappedStmt struct {
+	stmt  driver.Stmt
+	conn  *wrappedConn
+	query string
+}
+
+func (s *wrappedStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *wrappedStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *wrappedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *wrappedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *wrappedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if se, ok := s.stmt.(driver.StmtExecContext); ok {
+		result, err = se.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			result, err = s.stmt.Exec(values) //nolint:staticcheck // fallback for drivers without StmtExecContext
+		}
+	}
+	s.conn.logger.log(ctx, s.query, args, start, err)
+	return result, err
+}
+
+func (s *wrappedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if sq, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = sq.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = s.stmt.Query(values) //nolint:staticcheck // fallback for drivers without StmtQueryContext
+		}
+	}
+	s.conn.logger.log(ctx, s.query, args, start, err)
+	return rows, err
+}
+
+// CheckNamedValue keeps the argument conversion of the wrapped statement and
+// connection, which database/sql cannot see through the wrapper.
+func (s *wrappedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	if converter, ok := s.stmt.(driver.ColumnConverter); ok { //nolint:staticcheck // supported by database/sql
+		value, err := converter.ColumnConverter(nv.Ordinal - 1).ConvertValue(nv.Value)
+		if err != nil {
+			return err
+		}
+		nv.Value = value
+		return nil
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+type wrappedTx struct {
+	tx     driver.Tx
+	ctx    context.Context
+	logger *statementLogger
+}
+
+func (t *wrappedTx) Commit() error {
+	err := t.tx.Commit()
+	t.logger.logOperation(t.ctx, lg.SQLCommit, err)
+	return err
+}
+
+func (t *wrappedTx) Rollback() error {
+	err := t.tx.Rollback()
+	t.logger.logOperation(t.ctx, lg.SQLRollback, err)
+	return err
+}
+
+func valuesToNamed(values []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(values))
+	for i, value := range values {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+	}
+	return named
+}
+
+func namedToValues(named []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(named))
+	for i, arg := range named {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("sql: driver does not support the use of named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,431 @@
+package logger_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	sqlg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/sql_logger"
+)
+
+var errFake = errors.New("fake failure")
+
+// sqlStateError mimics driver errors carrying an SQLSTATE code.
+type sqlStateError struct{ state string }
+
+func (e sqlStateError) Error() string    { return "sqlstate " + e.state }
+func (e sqlStateError) SQLState() string { return e.state }
+
+// fakeDriver fails statements containing "fail", fails with a unique
+// violation on "duplicate", sleeps on "slow" and refuses to open "fail".
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	if name == "fail" {
+		return nil, errFake
+	}
+	return &fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := run(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := run(query); err != nil {
+		return nil, err
+	}
+	return fakeRows{}, nil
+}
+
+type fakeStmt struct{ query string }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if err := run(s.query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if err := run(s.query); err != nil {
+		return nil, err
+	}
+	return fakeRows{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return errFake }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"id"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+func run(query string) error {
+	if strings.Contains(query, "slow") {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if strings.Contains(query, "duplicate") {
+		return fmt.Errorf("exec: %w", sqlStateError{state: "23505"})
+	}
+	if strings.Contains(query, "fail") {
+		return errFake
+	}
+	return nil
+}
+
+func init() {
+	sql.Register("fakesql", fakeDriver{})
+}
+
+type record struct {
+	level     string
+	msg       string
+	operation lg.SQLErrorType
+	table     string
+	err       error
+	fields    []lg.Field
+}
+
+func (r record) field(key string) (any, bool) {
+	for _, field := range r.fields {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// recorder is an lg.Logger keeping the records in memory.
+type recorder struct {
+	mu      *sync.Mutex
+	records *[]record
+	fields  []lg.Field
+}
+
+func newRecorder() *recorder {
+	return &recorder{mu: new(sync.Mutex), records: new([]record)}
+}
+
+func (r *recorder) add(rec record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec.fields = append(append([]lg.Field{}, r.fields...), rec.fields...)
+	*r.records = append(*r.records, rec)
+}
+
+func (r *recorder) all() []record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]record{}, *r.records...)
+}
+
+func (r *recorder) WithFields(fields ...lg.Field) lg.Logger {
+	return &recorder{mu: r.mu, records: r.records, fields: append(append([]lg.Field{}, r.fields...), fields...)}
+}
+
+func (r *recorder) Debug(msg string, fields ...lg.Field) {
+	r.add(record{level: "debug", msg: msg, fields: fields})
+}
+
+func (r *recorder) Info(msg string, fields ...lg.Field) {
+	r.add(record{level: "info", msg: msg, fields: fields})
+}
+
+func (r *recorder) Warning(msg string, fields ...lg.Field) {
+	r.add(record{level: "warning", msg: msg, fields: fields})
+}
+
+func (r *recorder) Error(msg string, fields ...lg.Field) {
+	r.add(record{level: "error", msg: msg, fields: fields})
+}
+
+func (r *recorder) ErrorIn(funcName string, err error, fields ...lg.Field) {
+	r.add(record{level: "error", msg: funcName, err: err, fields: fields})
+}
+
+func (r *recorder) ErrorSQL(operation lg.SQLErrorType, table string, err error, fields ...lg.Field) {
+	r.add(record{level: "sql", operation: operation, table: table, err: err, fields: fields})
+}
+
+func (r *recorder) ErrorSQLSelect(table string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLSelect, table, err, fields...)
+}
+
+func (r *recorder) ErrorSQLInsert(table string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLInsert, table, err, fields...)
+}
+
+func (r *recorder) ErrorSQLUpdate(table string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLUpdate, table, err, fields...)
+}
+
+func (r *recorder) ErrorSQLDelete(table string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLDelete, table, err, fields...)
+}
+
+func (r *recorder) ErrorSQLBegin(err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLBegin, "", err, fields...)
+}
+
+func (r *recorder) ErrorSQLCommit(err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLCommit, "", err, fields...)
+}
+
+func (r *recorder) ErrorSQLRollback(err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLRollback, "", err, fields...)
+}
+
+func (r *recorder) ErrorSQLDDL(table string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLDDL, table, err, fields...)
+}
+
+func (r *recorder) ErrorSQLMigration(migration string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLMigration, migration, err, fields...)
+}
+
+func (r *recorder) ErrorSQLUpsert(table string, err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLUpsert, table, err, fields...)
+}
+
+func (r *recorder) ErrorSQLConnection(err error, fields ...lg.Field) {
+	r.ErrorSQL(lg.SQLConnection, "", err, fields...)
+}
+
+func (r *recorder) End()                {}
+func (r *recorder) EndWithError(*error) {}
+
+func openDB(t *testing.T, opts sqlg.Options) (*sql.DB, *recorder) {
+	t.Helper()
+	return openDSN(t, "", opts)
+}
+
+func openDSN(t *testing.T, dsn string, opts sqlg.Options) (*sql.DB, *recorder) {
+	t.Helper()
+	rec := newRecorder()
+	db, err := sqlg.Open("fakesql", dsn, rec, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, rec
+}
+
+func TestFailedStatements(t *testing.T) {
+	tests := []struct {
+		query     string
+		operation lg.SQLErrorType
+		table     string
+	}{
+		{"SELECT id FROM pets WHERE fail = 1", lg.SQLSelect, "pets"},
+		{"select id from (select id from pets) fail", lg.SQLSelect, ""},
+		{"WITH recent AS (SELECT id FROM pets) SELECT id FROM recent WHERE fail", lg.SQLSelect, "recent"},
+		{`INSERT INTO "public"."pets" (name) VALUES ($1) -- fail`, lg.SQLInsert, "public.pets"},
+		{"/* fail */ UPDATE pets SET name = 'FROM x'", lg.SQLUpdate, "pets"},
+		{"DELETE FROM `pets` WHERE fail", lg.SQLDelete, "pets"},
+		{"INSERT INTO pets (id) VALUES (1) ON CONFLICT (id) DO NOTHING -- fail", lg.SQLUpsert, "pets"},
+		{"INSERT INTO pets (id) VALUES (1) ON DUPLICATE KEY UPDATE fail = 1", lg.SQLUpsert, "pets"},
+		{"MERGE INTO pets USING fail ON pets.id = fail.id WHEN MATCHED THEN DELETE", lg.SQLUpsert, "pets"},
+		{"CREATE TABLE IF NOT EXISTS pets (fail int)", lg.SQLDDL, "pets"},
+		{"CREATE UNIQUE INDEX pets_fail ON pets (name)", lg.SQLDDL, "pets"},
+		{"ALTER TABLE public.pets ADD COLUMN fail int", lg.SQLDDL, "public.pets"},
+		{"DROP TABLE fail", lg.SQLDDL, "fail"},
+		{"TRUNCATE pets -- fail", lg.SQLDDL, "pets"},
+		{"BEGIN -- fail", lg.SQLBegin, ""},
+		{"COMMIT -- fail", lg.SQLCommit, ""},
+		{"ROLLBACK TO SAVEPOINT fail", lg.SQLRollback, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			db, rec := openDB(t, sqlg.Options{})
+
+			_, err := db.Exec(tt.query, 1)
+			require.ErrorIs(t, err, errFake)
+
+			records := rec.all()
+			require.Len(t, records, 1)
+			require.Equal(t, "sql", records[0].level)
+			require.Equal(t, tt.operation, records[0].operation)
+			require.Equal(t, tt.table, records[0].table)
+			require.ErrorIs(t, records[0].err, errFake)
+
+			query, _ := records[0].field(sqlg.QueryKey)
+			require.Equal(t, tt.query, query)
+			_, ok := records[0].field(sqlg.ArgsKey)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestUnknownStatementFailure(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{})
+
+	_, err := db.Exec("VACUUM fail")
+	require.ErrorIs(t, err, errFake)
+
+	records := rec.all()
+	require.Len(t, records, 1)
+	require.Equal(t, "error", records[0].level)
+	require.Equal(t, "vacuum", records[0].msg)
+}
+
+func TestSuccessfulStatementsAreNotLogged(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{SlowThreshold: time.Second})
+
+	rows, err := db.Query("SELECT id FROM pets")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	_, err = db.Exec("DELETE FROM pets")
+	require.NoError(t, err)
+
+	require.Empty(t, rec.all())
+}
+
+func TestSlowStatement(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{SlowThreshold: 5 * time.Millisecond})
+
+	rows, err := db.Query("SELECT slow FROM pets")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	records := rec.all()
+	require.Len(t, records, 1)
+	require.Equal(t, "warning", records[0].level)
+	require.Equal(t, lg.MsgSQLSlowStatement, records[0].msg)
+
+	table, _ := records[0].field(sqlg.TableKey)
+	require.Equal(t, "pets", table)
+	duration, _ := records[0].field(lg.DurationKey)
+	require.GreaterOrEqual(t, duration, 20*time.Millisecond)
+	threshold, _ := records[0].field(lg.SlowThresholdKey)
+	require.Equal(t, 5*time.Millisecond, threshold)
+}
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts sqlg.Options
+		want lg.GroupValue
+	}{
+		{
+			name: "plain",
+			opts: sqlg.Options{LogArgs: true},
+			want: lg.GroupValue{lg.Any("1", int64(42)), lg.Any("name", "rex")},
+		},
+		{
+			name: "masked",
+			opts: sqlg.Options{LogArgs: true, RedactArg: sqlg.MaskArg},
+			want: lg.GroupValue{lg.Any("1", "***"), lg.Any("name", "***")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := openDB(t, tt.opts)
+
+			_, err := db.Exec("INSERT INTO pets VALUES (?, ?) -- fail", 42, sql.Named("name", "rex"))
+			require.ErrorIs(t, err, errFake)
+
+			records := rec.all()
+			require.Len(t, records, 1)
+			args, _ := records[0].field(sqlg.ArgsKey)
+			require.Equal(t, tt.want, args)
+		})
+	}
+}
+
+func TestContextFields(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{})
+
+	ctx := lg.WithContextFields(context.Background(), lg.String("request_id", "r1"))
+	_, err := db.ExecContext(ctx, "UPDATE pets SET fail = 1")
+	require.ErrorIs(t, err, errFake)
+
+	records := rec.all()
+	require.Len(t, records, 1)
+	requestID, _ := records[0].field("request_id")
+	require.Equal(t, "r1", requestID)
+}
+
+func TestPreparedStatement(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{})
+
+	stmt, err := db.Prepare("DELETE FROM pets WHERE fail = ?")
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	_, err = stmt.Exec(1)
+	require.ErrorIs(t, err, errFake)
+
+	records := rec.all()
+	require.Len(t, records, 1)
+	require.Equal(t, lg.SQLDelete, records[0].operation)
+	require.Equal(t, "pets", records[0].table)
+}
+
+func TestCommitFailure(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{})
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.ErrorIs(t, tx.Commit(), errFake)
+
+	records := rec.all()
+	require.Len(t, records, 1)
+	require.Equal(t, "sql", records[0].level)
+	require.Equal(t, lg.SQLCommit, records[0].operation)
+	require.ErrorIs(t, records[0].err, errFake)
+}
+
+func TestConnectionFailure(t *testing.T) {
+	db, rec := openDSN(t, "fail", sqlg.Options{})
+
+	require.ErrorIs(t, db.Ping(), errFake)
+
+	records := rec.all()
+	require.NotEmpty(t, records)
+	require.Equal(t, lg.SQLConnection, records[0].operation)
+	require.ErrorIs(t, records[0].err, errFake)
+}
+
+func TestSQLState(t *testing.T) {
+	db, rec := openDB(t, sqlg.Options{})
+
+	_, err := db.Exec("INSERT INTO pets (id) VALUES (1) -- duplicate")
+	require.Error(t, err)
+
+	records := rec.all()
+	require.Len(t, records, 1)
+	require.Equal(t, lg.SQLInsert, records[0].operation)
+	require.Equal(t, "23505", lg.SQLState(records[0].err))
+	require.Contains(t, lg.EncodeError(records[0].err), lg.String(lg.SQLStateKey, "23505"))
+}
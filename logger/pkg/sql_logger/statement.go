@@ -0,0 +1,216 @@
+package logger
+
+import (
+	"strings"
+	"unicode"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// statement is the result of classifying an SQL query.
+type statement struct {
+	verb      string
+	operation lg.SQLErrorType
+	known     bool
+	table     string
+}
+
+// classify returns the operation and the main table of query. Leading
+// comments and WITH clauses are skipped, the verb is the first keyword of the
+// main statement and the table the identifier following FROM, INTO or UPDATE.
+func classify(query string) statement {
+	tokens := tokenize(query)
+	if len(tokens) == 0 {
+		return statement{}
+	}
+
+	i := 0
+	if strings.EqualFold(tokens[0], "WITH") {
+		i = skipWith(tokens)
+	}
+	if i >= len(tokens) {
+		return statement{verb: strings.ToLower(tokens[0])}
+	}
+
+	st := statement{verb: strings.ToLower(tokens[i])}
+	rest := tokens[i+1:]
+	switch st.verb {
+	case "select":
+		st.operation, st.known = lg.SQLSelect, true
+		st.table = identifierAfter(rest, "FROM")
+	case "insert":
+		st.operation, st.known = lg.SQLInsert, true
+		if isUpsert(rest) {
+			st.operation = lg.SQLUpsert
+		}
+		st.table = identifierAfter(rest, "INTO")
+	case "replace", "merge", "upsert":
+		st.operation, st.known = lg.SQLUpsert, true
+		st.table = identifierAfter(rest, "INTO")
+	case "update":
+		st.operation, st.known = lg.SQLUpdate, true
+		st.table = identifierAt(rest, 0)
+	case "delete":
+		st.operation, st.known = lg.SQLDelete, true
+		st.table = identifierAfter(rest, "FROM")
+	case "begin", "start":
+		st.operation, st.known = lg.SQLBegin, true
+	case "commit", "end":
+		st.operation, st.known = lg.SQLCommit, true
+	case "rollback", "abort":
+		st.operation, st.known = lg.SQLRollback, true
+	case "create", "alter", "drop", "truncate", "rename", "comment":
+		st.operation, st.known = lg.SQLDDL, true
+		st.table = schemaObject(rest)
+	}
+	return st
+}
+
+// isUpsert reports whether an INSERT resolves conflicts by updating,
+// like ON CONFLICT in PostgreSQL and SQLite or ON DUPLICATE KEY in MySQL.
+func isUpsert(tokens []string) bool {
+	for i := 0; i+1 < len(tokens); i++ {
+		if strings.EqualFold(tokens[i], "ON") &&
+			(strings.EqualFold(tokens[i+1], "CONFLICT") || strings.EqualFold(tokens[i+1], "DUPLICATE")) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaObject returns the name of the object changed by a DDL statement,
+// for CREATE INDEX the indexed table.
+func schemaObject(tokens []string) string {
+	for i, token := range tokens {
+		switch strings.ToUpper(token) {
+		case "INDEX":
+			if table := identifierAfter(tokens[i+1:], "ON"); table != "" {
+				return table
+			}
+		case "TABLE", "VIEW", "SEQUENCE", "SCHEMA", "TYPE", "FUNCTION", "TRIGGER", "DATABASE":
+			return identifierAt(tokens, i+1)
+		}
+	}
+	return identifierAt(tokens, 0)
+}
+
+// skipWith returns the index of the first token after the common table
+// expressions of a WITH clause.
+func skipWith(tokens []string) int {
+	depth := 0
+	for i := 1; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		default:
+			if depth == 0 && isStatementVerb(tokens[i]) {
+				return i
+			}
+		}
+	}
+	return len(tokens)
+}
+
+func isStatementVerb(token string) bool {
+	switch strings.ToUpper(token) {
+	case "SELECT", "INSERT", "REPLACE", "MERGE", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+// identifierAfter returns the identifier following the first keyword at the
+// top nesting level of tokens.
+func identifierAfter(tokens []string, keyword string) string {
+	depth := 0
+	for i, token := range tokens {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		default:
+			if depth == 0 && strings.EqualFold(token, keyword) {
+				return identifierAt(tokens, i+1)
+			}
+		}
+	}
+	return ""
+}
+
+func identifierAt(tokens []string, i int) string {
+	for ; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "ONLY", "IGNORE", "LOW_PRIORITY", "OR", "INTO", "FROM",
+			"IF", "NOT", "EXISTS", "UNIQUE", "TEMP", "TEMPORARY", "MATERIALIZED", "CONCURRENTLY":
+			continue
+		case "(":
+			return ""
+		}
+		return unquote(tokens[i])
+	}
+	return ""
+}
+
+// unquote strips identifier quotes from every part of a qualified name.
+func unquote(identifier string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(part, "\"`[]")
+	}
+	return strings.Join(parts, ".")
+}
+
+// tokenize splits query into words, quoted identifiers and parentheses,
+// dropping comments, string literals and other punctuation.
+func tokenize(query string) []string {
+	var tokens []string
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			i = skipPast(query, i, "\n")
+		case strings.HasPrefix(query[i:], "/*"):
+			i = skipPast(query, i+2, "*/")
+		case c == '\'':
+			i = skipPast(query, i+1, "'")
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case isIdentifierByte(c):
+			start := i
+			for i < len(query) && (isIdentifierByte(query[i]) || query[i] == '.') {
+				i = skipQuoted(query, i)
+			}
+			tokens = append(tokens, query[start:i])
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+// skipQuoted returns the index after the quoted part starting at i, or i+1.
+func skipQuoted(query string, i int) int {
+	switch query[i] {
+	case '"', '`':
+		return skipPast(query, i+1, query[i:i+1])
+	case '[':
+		return skipPast(query, i+1, "]")
+	}
+	return i + 1
+}
+
+func skipPast(query string, i int, end string) int {
+	if idx := strings.Index(query[i:], end); idx != -1 {
+		return i + idx + len(end)
+	}
+	return len(query)
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c == '"' || c == '`' || c == '[' ||
+		c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	StacktraceKey = "stacktrace"
+
+	maxStackDepth = 64
+)
+
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Stack is a captured call stack, rendered by slog as a group of frame groups
+// keyed by their index.
+type Stack []Frame
+
+// CaptureStack returns the stack of the caller, skipping skip additional frames.
+// Called by a function deferred while panicking, as when recovering, it returns
+// the stack of the code that panicked instead, dropping the frames up to and
+// including runtime.gopanic.
+func CaptureStack(skip int) Stack {
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(skip+2, pcs)]
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == gopanic {
+			pcs = pcs[i+1:]
+			break
+		}
+	}
+	return stackOf(pcs)
+}
+
+// CaptureStackFrom returns the stack starting at the frame of pc, a program
+// counter of a caller returned by CallerPC.
+func CaptureStackFrom(pc uintptr) Stack {
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	for i := range pcs {
+		if pcs[i] == pc {
+			pcs = pcs[i:]
+			break
+		}
+	}
+	return stackOf(pcs)
+}
+
+func stackOf(pcs []uintptr) Stack {
+	frames := runtime.CallersFrames(pcs)
+
+	stack := make(Stack, 0, len(pcs))
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+func (s Stack) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(s))
+	for i, frame := range s {
+		attrs = append(attrs, slog.Group(strconv.Itoa(i),
+			slog.String("function", frame.Function),
+			slog.String("file", frame.File),
+			slog.Int("line", frame.Line)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// String renders the stack like runtime/debug.Stack: a function per line
+// followed by its indented file and line.
+func (s Stack) String() string {
+	var b strings.Builder
+	for _, frame := range s {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+func panicking() { panic("boom") }
+
+func TestCaptureStack(t *testing.T) {
+	stack := lg.CaptureStack(0)
+	require.NotEmpty(t, stack)
+	require.True(t, strings.HasSuffix(stack[0].Function, ".TestCaptureStack"), stack[0].Function)
+	require.True(t, strings.HasSuffix(stack[0].File, "pkg/stack_test.go"), stack[0].File)
+}
+
+// A stack captured while recovering starts at the code that panicked.
+func TestCaptureStackPanicking(t *testing.T) {
+	var stack lg.Stack
+	func() {
+		defer func() {
+			_ = recover()
+			stack = lg.CaptureStack(0)
+		}()
+		panicking()
+	}()
+
+	require.GreaterOrEqual(t, len(stack), 2)
+	require.True(t, strings.HasSuffix(stack[0].Function, ".panicking"), stack[0].Function)
+	require.True(t, strings.HasSuffix(stack[1].Function, ".TestCaptureStackPanicking.func1"), stack[1].Function)
+	for _, frame := range stack {
+		require.False(t, strings.HasPrefix(frame.Function, "runtime.gopanic"), stack.String())
+	}
+}
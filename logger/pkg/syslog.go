@@ -0,0 +1,340 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DestinationSyslog is the destination of sinks sending RFC 5424
+	// messages to a syslog server, configured by SinkConfig.Syslog.
+	DestinationSyslog = "syslog"
+
+	SyslogUDP      = "udp"
+	SyslogTCP      = "tcp"
+	SyslogUnix     = "unix"
+	SyslogUnixgram = "unixgram"
+
+	// DefaultSyslogStructuredDataID is the SD-ID of the structured data
+	// element holding the fields, under the private enterprise number
+	// reserved for documentation by RFC 5612.
+	DefaultSyslogStructuredDataID = "fields@32473"
+
+	syslogTimeLayout  = "2006-01-02T15:04:05.000000Z07:00"
+	syslogNilValue    = "-"
+	syslogMaxHostname = 255
+	syslogMaxAppName  = 48
+	syslogMaxParam    = 32
+	syslogDialTimeout = 5 * time.Second
+	// syslogRedialInterval is the time writers fail fast after a failed dial.
+	syslogRedialInterval = time.Second
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// localSyslogSockets are tried in order without SyslogConfig.Address.
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig configures the RFC 5424 messages of syslog sinks. The fields
+// of records are the parameters of a structured data element with their
+// groups joined by dots.
+type SyslogConfig struct {
+	// Network is SyslogUDP, SyslogTCP, SyslogUnix or SyslogUnixgram, the
+	// local syslog socket (/dev/log) is used without Network and Address.
+	// Stream transports frame messages by octet counting (RFC 6587).
+	Network string
+	Address string
+	// Facility is a facility name such as "daemon" or "local0" (default "user").
+	Facility string
+	// AppName is the APP-NAME of messages, Config.ServiceName by default.
+	AppName string
+	// StructuredDataID is the SD-ID of the fields, DefaultSyslogStructuredDataID
+	// by default.
+	StructuredDataID string
+}
+
+// Validate reports configuration errors.
+func (c SyslogConfig) Validate() error {
+	if _, ok := syslogFacilities[c.facilityName()]; !ok {
+		return fmt.Errorf("unknown syslog facility: %s", c.Facility)
+	}
+	switch c.Network {
+	case "":
+		if c.Address != "" {
+			return fmt.Errorf("syslog address %s without network", c.Address)
+		}
+	case SyslogUDP, SyslogTCP, SyslogUnix, SyslogUnixgram:
+	default:
+		return fmt.Errorf("unknown syslog network: %s", c.Network)
+	}
+	return nil
+}
+
+func (c SyslogConfig) facilityName() string {
+	if c.Facility == "" {
+		return "user"
+	}
+	return strings.ToLower(c.Facility)
+}
+
+// errSyslogUnavailable is returned while the server is dialed again.
+var errSyslogUnavailable = errors.New("syslog server unavailable")
+
+// SyslogWriter sends records to a syslog server as RFC 5424 messages. A
+// connection failing, like the datagram socket of a restarted syslog daemon,
+// is dialed again for the message. It is safe for concurrent use.
+type SyslogWriter struct {
+	cfg      SyslogConfig
+	header   string
+	facility int
+	origin   string
+
+	mu       sync.Mutex
+	conn     net.Conn
+	stream   bool
+	dialing  bool
+	redialAt time.Time
+	closed   bool
+}
+
+var _ RecordWriter = (*SyslogWriter)(nil)
+
+// NewSyslogWriter connects to the syslog server. The service name is the
+// default APP-NAME and, with the version, sent in the origin element.
+func NewSyslogWriter(cfg SyslogConfig, serviceName, version string) (*SyslogWriter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = serviceName
+	}
+	if cfg.StructuredDataID == "" {
+		cfg.StructuredDataID = DefaultSyslogStructuredDataID
+	}
+
+	hostname, _ := os.Hostname()
+	w := &SyslogWriter{
+		cfg:      cfg,
+		facility: syslogFacilities[cfg.facilityName()],
+		header: " " + syslogHeaderField(hostname, syslogMaxHostname) +
+			" " + syslogHeaderField(cfg.AppName, syslogMaxAppName) +
+			" " + strconv.Itoa(os.Getpid()) +
+			" " + syslogNilValue + " ",
+	}
+	if serviceName != "" || version != "" {
+		origin := []byte("[origin")
+		if serviceName != "" {
+			origin = appendSyslogParam(origin, "software", serviceName)
+		}
+		if version != "" {
+			origin = appendSyslogParam(origin, "swVersion", version)
+		}
+		w.origin = string(append(origin, ']'))
+	}
+
+	conn, stream, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+	w.conn, w.stream = conn, stream
+	return w, nil
+}
+
+func (w *SyslogWriter) dial() (net.Conn, bool, error) {
+	if w.cfg.Network != "" {
+		conn, err := net.DialTimeout(w.cfg.Network, w.cfg.Address, syslogDialTimeout)
+		if err != nil {
+			return nil, false, fmt.Errorf("syslog %s %s: %w", w.cfg.Network, w.cfg.Address, err)
+		}
+		return conn, w.cfg.Network == SyslogTCP || w.cfg.Network == SyslogUnix, nil
+	}
+
+	for _, path := range localSyslogSockets {
+		for _, network := range []string{SyslogUnixgram, SyslogUnix} {
+			if conn, err := net.DialTimeout(network, path, syslogDialTimeout); err == nil {
+				return conn, network == SyslogUnix, nil
+			}
+		}
+	}
+	return nil, false, errors.New("no local syslog socket found")
+}
+
+// WriteRecord sends r, dialing the server again once when the connection
+// failed.
+func (w *SyslogWriter) WriteRecord(r Record) error {
+	msg := w.AppendMessage(nil, r)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if err := w.write(msg); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	if err := w.redial(); err != nil {
+		return err
+	}
+	return w.write(msg)
+}
+
+// redial connects to the server again with w.mu released, so that a server
+// down does not block other writers: they fail fast while a dial is in
+// progress and for syslogRedialInterval after it failed.
+func (w *SyslogWriter) redial() error {
+	if w.closed {
+		return net.ErrClosed
+	}
+	if w.dialing || time.Now().Before(w.redialAt) {
+		return errSyslogUnavailable
+	}
+
+	w.dialing = true
+	w.mu.Unlock()
+	conn, stream, err := w.dial()
+	w.mu.Lock()
+	w.dialing = false
+
+	if err != nil {
+		w.redialAt = time.Now().Add(syslogRedialInterval)
+		return err
+	}
+	if w.closed {
+		_ = conn.Close()
+		return net.ErrClosed
+	}
+	w.conn, w.stream = conn, stream
+	return nil
+}
+
+func (w *SyslogWriter) write(msg []byte) error {
+	if w.stream {
+		frame := strconv.AppendInt(nil, int64(len(msg)), 10)
+		msg = append(append(frame, ' '), msg...)
+	}
+	_, err := w.conn.Write(msg)
+	return err
+}
+
+// AppendMessage appends the RFC 5424 message of r.
+func (w *SyslogWriter) AppendMessage(b []byte, r Record) []byte {
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(w.facility*8+SyslogSeverity(r.Severity)), 10)
+	b = append(b, ">1 "...)
+	if r.Time.IsZero() {
+		b = append(b, syslogNilValue...)
+	} else {
+		b = r.Time.AppendFormat(b, syslogTimeLayout)
+	}
+	b = append(b, w.header...)
+
+	n := len(b)
+	b = append(b, '[')
+	b = append(b, w.cfg.StructuredDataID...)
+	if frame, ok := r.Frame(); ok {
+		b = appendSyslogParam(b, CallerKey, ShortPath(frame.File)+":"+strconv.Itoa(frame.Line))
+	}
+	FlattenFields(r.Attributes, ".", func(key string, value any) {
+		b = appendSyslogParam(b, key, FormatValue(value))
+	})
+	b = append(b, ']')
+	if len(b) == n+len(w.cfg.StructuredDataID)+2 {
+		b = b[:n]
+	}
+	b = append(b, w.origin...)
+	if len(b) == n {
+		b = append(b, syslogNilValue...)
+	}
+
+	if r.Message != "" {
+		b = append(b, ' ')
+		b = append(b, r.Message...)
+	}
+	return b
+}
+
+// Flush does nothing, messages are sent by WriteRecord.
+func (w *SyslogWriter) Flush() error {
+	return nil
+}
+
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// SyslogSeverity maps severity numbers of Record to syslog severities.
+func SyslogSeverity(severity int32) int {
+	switch {
+	case severity < SeverityInfo:
+		return 7 // debug
+	case severity < SeverityWarn:
+		return 6 // informational
+	case severity < SeverityError:
+		return 4 // warning
+	case severity < SeverityFatal:
+		return 3 // error
+	default:
+		return 2 // critical
+	}
+}
+
+// syslogHeaderField keeps the printable ASCII characters of s, at most limit.
+func syslogHeaderField(s string, limit int) string {
+	field := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(field) < limit; i++ {
+		if s[i] > ' ' && s[i] <= '~' {
+			field = append(field, s[i])
+		}
+	}
+	if len(field) == 0 {
+		return syslogNilValue
+	}
+	return string(field)
+}
+
+// appendSyslogParam appends ` name="value"` with the characters not allowed
+// in parameter names replaced by _ and ", \ and ] escaped in the value.
+func appendSyslogParam(b []byte, name, value string) []byte {
+	b = append(b, ' ')
+	n := 0
+	for i := 0; i < len(name) && n < syslogMaxParam; i++ {
+		c := name[i]
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b = append(b, c)
+		n++
+	}
+	if n == 0 {
+		b = append(b, '_')
+	}
+	b = append(b, '=', '"')
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '"', '\\', ']':
+			b = append(b, '\\')
+		}
+		b = append(b, value[i])
+	}
+	return append(b, '"')
+}
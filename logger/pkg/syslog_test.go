@@ -0,0 +1,240 @@
+package logger_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	slg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/slog_logger"
+)
+
+// listenSyslog starts a local syslog server and returns its address and the
+// messages it receives, unframed for stream transports.
+func listenSyslog(t *testing.T, network string) (string, <-chan string) {
+	t.Helper()
+	address := "127.0.0.1:0"
+	if network == lg.SyslogUnix || network == lg.SyslogUnixgram {
+		address = filepath.Join(t.TempDir(), "log.sock")
+	}
+	address, messages, stop := startSyslog(t, network, address)
+	t.Cleanup(stop)
+	return address, messages
+}
+
+// startSyslog starts a syslog server at address and returns its address, the
+// messages it receives and a function stopping it with its connections.
+func startSyslog(t *testing.T, network, address string) (string, <-chan string, func()) {
+	t.Helper()
+	messages := make(chan string, 64)
+
+	if network == lg.SyslogUDP || network == lg.SyslogUnixgram {
+		conn, err := net.ListenPacket(network, address)
+		require.NoError(t, err)
+		go func() {
+			buf := make([]byte, 64<<10)
+			for {
+				n, _, err := conn.ReadFrom(buf)
+				if err != nil {
+					return
+				}
+				messages <- string(buf[:n])
+			}
+		}()
+		return conn.LocalAddr().String(), messages, func() {
+			_ = conn.Close()
+			if network == lg.SyslogUnixgram {
+				_ = os.Remove(address)
+			}
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var conns []net.Conn
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			go readOctetCounted(conn, messages)
+		}
+	}()
+	return listener.Addr().String(), messages, func() {
+		_ = listener.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}
+}
+
+func readOctetCounted(conn net.Conn, messages chan<- string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+		if err != nil {
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return
+		}
+		messages <- string(msg)
+	}
+}
+
+// receiveMessage returns the first message ending with suffix.
+func receiveMessage(t *testing.T, messages <-chan string, suffix string) string {
+	t.Helper()
+	timeout := time.After(receiveTimeout)
+	for {
+		select {
+		case msg := <-messages:
+			if strings.HasSuffix(msg, suffix) {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("no syslog message ending with %q", suffix)
+		}
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	networks := []string{lg.SyslogUDP, lg.SyslogTCP, lg.SyslogUnix, lg.SyslogUnixgram}
+	for _, b := range backends {
+		for _, network := range networks {
+			t.Run(b.name+"/"+network, func(t *testing.T) {
+				address, messages := listenSyslog(t, network)
+				factory, err := b.newFactory(lg.Config{
+					ServiceName: "words",
+					Version:     "1.2.3",
+					LogLevel:    "info",
+					Sinks: []lg.SinkConfig{{
+						Destination: lg.DestinationSyslog,
+						Syslog:      &lg.SyslogConfig{Network: network, Address: address, Facility: "local0"},
+					}},
+				})
+				require.NoError(t, err)
+
+				logger := factory.Named(traceContext(), "worker")
+				logger.Debug("skipped")
+				logger.Info("hello", lg.String("user", "ann"), lg.String("quote", `say "hi" [x]`),
+					lg.Group("request", lg.String("method", "GET")))
+				logger.Warning("slow")
+				require.NoError(t, factory.Close())
+
+				hello := receiveMessage(t, messages, " hello")
+				header := strings.SplitN(hello, " ", 7)
+				require.Equal(t, "<134>1", header[0])
+				_, err = time.Parse(time.RFC3339Nano, header[1])
+				require.NoError(t, err)
+				require.Equal(t, "words", header[3])
+				require.Equal(t, strconv.Itoa(os.Getpid()), header[4])
+				require.Equal(t, "-", header[5])
+
+				data := header[6]
+				require.True(t, strings.HasPrefix(data, `[fields@32473 caller="pkg/syslog_test.go:`), data)
+				require.Contains(t, data, ` trace_id="`+traceID.String()+`"`)
+				require.Contains(t, data, ` span_id="`+spanID.String()+`"`)
+				require.Contains(t, data, ` user="ann"`)
+				require.Contains(t, data, ` quote="say \"hi\" [x\]"`)
+				require.Contains(t, data, ` request.method="GET"`)
+				require.Contains(t, data, `][origin software="words" swVersion="1.2.3"] `)
+
+				slow := receiveMessage(t, messages, " slow")
+				require.True(t, strings.HasPrefix(slow, "<132>1 "), slow)
+			})
+		}
+	}
+}
+
+func TestSyslogConfigErrors(t *testing.T) {
+	tests := []struct {
+		cfg lg.SyslogConfig
+		err string
+	}{
+		{lg.SyslogConfig{Facility: "local9"}, "unknown syslog facility"},
+		{lg.SyslogConfig{Network: "sctp", Address: "localhost:514"}, "unknown syslog network"},
+		{lg.SyslogConfig{Address: "localhost:514"}, "without network"},
+	}
+	for _, tc := range tests {
+		_, err := slg.NewLoggerFactory(lg.Config{Sinks: []lg.SinkConfig{{Destination: lg.DestinationSyslog, Syslog: &tc.cfg}}})
+		require.ErrorContains(t, err, tc.err)
+	}
+}
+
+// The writer dials the server again after a restart, for the datagram socket
+// of a local daemon as for stream transports.
+func TestSyslogReconnect(t *testing.T) {
+	networks := []string{lg.SyslogUnixgram, lg.SyslogUDP, lg.SyslogTCP, lg.SyslogUnix}
+	for _, network := range networks {
+		t.Run(network, func(t *testing.T) {
+			address := "127.0.0.1:0"
+			if network == lg.SyslogUnix || network == lg.SyslogUnixgram {
+				address = filepath.Join(t.TempDir(), "log.sock")
+			}
+			address, messages, stop := startSyslog(t, network, address)
+			w, err := lg.NewSyslogWriter(lg.SyslogConfig{Network: network, Address: address}, "words", "")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = w.Close() })
+
+			require.NoError(t, w.WriteRecord(lg.Record{Message: "before"}))
+			receiveMessage(t, messages, " before")
+
+			stop()
+			_, messages, stop = startSyslog(t, network, address)
+			t.Cleanup(stop)
+
+			// A stream connection may accept a message before it notices the
+			// peer has gone, so write until one arrives.
+			require.Eventually(t, func() bool {
+				_ = w.WriteRecord(lg.Record{Message: "after"})
+				select {
+				case msg := <-messages:
+					return strings.HasSuffix(msg, " after")
+				case <-time.After(10 * time.Millisecond):
+					return false
+				}
+			}, receiveTimeout, time.Millisecond)
+		})
+	}
+}
+
+// Writers fail fast instead of waiting for the dial of a server that is down.
+func TestSyslogServerDown(t *testing.T) {
+	address := filepath.Join(t.TempDir(), "log.sock")
+	address, _, stop := startSyslog(t, lg.SyslogUnixgram, address)
+	w, err := lg.NewSyslogWriter(lg.SyslogConfig{Network: lg.SyslogUnixgram, Address: address}, "words", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = w.Close() })
+	stop()
+
+	require.Error(t, w.WriteRecord(lg.Record{Message: "lost"}))
+	started := time.Now()
+	require.ErrorContains(t, w.WriteRecord(lg.Record{Message: "lost"}), "unavailable")
+	require.Less(t, time.Since(started), 100*time.Millisecond)
+
+	require.NoError(t, w.Close())
+	require.ErrorIs(t, w.WriteRecord(lg.Record{Message: "closed"}), net.ErrClosed)
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// consoleEncoder writes entries for humans like the console handler of the
+// slog backend: time, padded level, short caller and message in aligned
+// columns, then the fields as logfmt and stack traces as indented blocks.
+type consoleEncoder struct {
+	*logfmtEncoder
+	color        bool
+	callerPrefix string
+}
+
+func newConsoleEncoder(color bool, callerPrefix string) zapcore.Encoder {
+	return &consoleEncoder{
+		logfmtEncoder: &logfmtEncoder{buf: logfmtPool.Get()},
+		color:         color,
+		callerPrefix:  callerPrefix,
+	}
+}
+
+func (e *consoleEncoder) Clone() zapcore.Encoder {
+	return &consoleEncoder{
+		logfmtEncoder: e.logfmtEncoder.Clone().(*logfmtEncoder),
+		color:         e.color,
+		callerPrefix:  e.callerPrefix,
+	}
+}
+
+func (e *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := logfmtPool.Get()
+	line.AppendString(ent.Time.Format(lg.TimeLayout))
+	line.AppendByte(' ')
+	line.Write(lg.AppendConsoleLevel(nil, ent.Level.CapitalString(), e.color))
+	if ent.Caller.Defined {
+		line.AppendByte(' ')
+		line.Write(lg.AppendConsoleCaller(nil, e.callerPrefix+ent.Caller.TrimmedPath()))
+	}
+	line.AppendByte(' ')
+	line.AppendString(ent.Message)
+
+	var stacks []string
+	enc := e.logfmtEncoder.Clone().(*logfmtEncoder)
+	for _, field := range fields {
+		if stack, ok := field.Interface.(stackMarshaler); ok && field.Key == lg.StacktraceKey {
+			stacks = append(stacks, lg.Stack(stack).String())
+			continue
+		}
+		field.AddTo(enc)
+	}
+	if enc.buf.Len() > 0 {
+		line.AppendByte(' ')
+		line.Write(enc.buf.Bytes())
+	}
+	enc.buf.Free()
+	line.AppendString(zapcore.DefaultLineEnding)
+
+	if ent.Stack != "" {
+		stacks = append(stacks, ent.Stack)
+	}
+	for _, stack := range stacks {
+		line.Write(lg.AppendIndented(nil, stack))
+	}
+	return line, nil
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// failoverCore writes entries to all its cores like zapcore.NewTee, but
+// disables a failing core and writes the entries it failed on or skipped to
+// the fallback, see lg.FailoverConfig.
+type failoverCore struct {
+	cores []zapcore.Core
+	names []string
+	// states track the failures of the cores, they are shared by the cores
+	// derived with With.
+	states   []*lg.SinkFailures
+	fallback zapcore.Core
+	cfg      lg.FailoverConfig
+}
+
+// newFailoverCore returns the failover core of cores written to the
+// destinations names.
+func newFailoverCore(cfg lg.FailoverConfig, cores []zapcore.Core, names []string, fallback zapcore.Core) zapcore.Core {
+	states := make([]*lg.SinkFailures, len(cores))
+	for i := range states {
+		states[i] = lg.NewSinkFailures(cfg.MaxFailures, cfg.RetryAfter, cfg.MaxRetryAfter)
+	}
+	return &failoverCore{cores: cores, names: names, states: states, fallback: fallback, cfg: cfg}
+}
+
+func (c *failoverCore) Enabled(level zapcore.Level) bool {
+	for _, core := range c.cores {
+		if core.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *failoverCore) With(fields []zapcore.Field) zapcore.Core {
+	derived := *c
+	derived.cores = make([]zapcore.Core, len(c.cores))
+	for i, core := range c.cores {
+		derived.cores[i] = core.With(fields)
+	}
+	if c.fallback != nil {
+		derived.fallback = c.fallback.With(fields)
+	}
+	return &derived
+}
+
+func (c *failoverCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *failoverCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	now := time.Now()
+	errs := make([]error, len(c.cores))
+	skipped := false
+
+	var wg sync.WaitGroup
+	for i, core := range c.cores {
+		if !core.Enabled(ent.Level) {
+			continue
+		}
+		if !c.states[i].Enabled(now) {
+			skipped = true
+			continue
+		}
+		if !c.cfg.Concurrent {
+			errs[i] = c.write(i, ent, fields)
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.write(i, ent, fields)
+		}(i)
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if c.fallback != nil && (skipped || err != nil) && c.fallback.Enabled(ent.Level) {
+		if fallbackErr := c.fallback.Write(ent, fields); fallbackErr != nil {
+			err = errors.Join(err, fallbackErr)
+		}
+	}
+	return err
+}
+
+func (c *failoverCore) write(i int, ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.cores[i].Write(ent, fields)
+	c.states[i].Record(err, time.Now())
+	if err != nil && c.cfg.OnError != nil {
+		c.cfg.OnError(c.names[i], err)
+	}
+	return err
+}
+
+func (c *failoverCore) Sync() error {
+	errs := make([]error, 0, len(c.cores)+1)
+	for _, core := range c.cores {
+		errs = append(errs, core.Sync())
+	}
+	if c.fallback != nil {
+		errs = append(errs, c.fallback.Sync())
+	}
+	return errors.Join(errs...)
+}
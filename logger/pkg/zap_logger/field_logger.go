@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+var (
+	_ lg.Factory         = (*ZapLoggerFactory)(nil)
+	_ lg.Reopener        = (*ZapLoggerFactory)(nil)
+	_ lg.LevelController = (*ZapLoggerFactory)(nil)
+	_ lg.Logger          = (*fieldLogger)(nil)
+	_ lg.CallerSkipper   = (*fieldLogger)(nil)
+)
+
+// fieldLogger adapts ZapLogger to the backend-neutral lg.Logger interface.
+type fieldLogger struct {
+	logger *ZapLogger
+}
+
+func (f *ZapLoggerFactory) Get(ctx context.Context, fields ...lg.Field) lg.Logger {
+	pc := f.callerPC()
+	return &fieldLogger{logger: f.newLogger(ctx, pc, lg.FunctionName(pc), fieldsToZap(fields))}
+}
+
+func (f *ZapLoggerFactory) Named(ctx context.Context, name string, fields ...lg.Field) lg.Logger {
+	return &fieldLogger{logger: f.newLogger(ctx, f.callerPC(), name, fieldsToZap(fields))}
+}
+
+func (l *fieldLogger) WithFields(fields ...lg.Field) lg.Logger {
+	return &fieldLogger{logger: l.logger.WithFields(fieldsToZap(fields)...)}
+}
+
+func (l *fieldLogger) WithCallerSkip(skip int) lg.Logger {
+	return &fieldLogger{logger: l.logger.WithCallerSkip(skip)}
+}
+
+func (l *fieldLogger) Debug(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), zapcore.DebugLevel, msg, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) Info(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), zapcore.InfoLevel, msg, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) Warning(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), zapcore.WarnLevel, msg, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) Error(msg string, fields ...lg.Field) {
+	l.logger.logMessage(l.logger.callerPC(), zapcore.ErrorLevel, msg, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorIn(funcName string, err error, fields ...lg.Field) {
+	l.logger.errorIn(l.logger.callerPC(), funcName, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQL(operation SQLErrorType, table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), operation, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLSelect(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLSelect, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLInsert(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLInsert, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLUpdate(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLUpdate, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLDelete(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLDelete, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLBegin(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLBegin, "", err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLCommit(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLCommit, "", err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLRollback(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLRollback, "", err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLDDL(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLDDL, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLMigration(migration string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLMigration, migration, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLUpsert(table string, err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLUpsert, table, err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) ErrorSQLConnection(err error, fields ...lg.Field) {
+	l.logger.errorSQL(l.logger.callerPC(), SQLConnection, "", err, fieldsToZap(fields))
+}
+
+func (l *fieldLogger) End() {
+	l.logger.end(l.logger.callerPC(), recover(), nil)
+}
+
+func (l *fieldLogger) EndWithError(errPtr *error) {
+	l.logger.end(l.logger.callerPC(), recover(), errPtr)
+}
+
+func fieldsToZap(fields []lg.Field) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	zapFields := make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		zapFields = append(zapFields, fieldToZap(field))
+	}
+	return zapFields
+}
+
+func fieldToZap(field lg.Field) zap.Field {
+	if group, ok := field.Value.(lg.GroupValue); ok {
+		return zap.Object(field.Key, groupMarshaler(group))
+	}
+	if stack, ok := field.Value.(lg.Stack); ok {
+		return zap.Object(field.Key, stackMarshaler(stack))
+	}
+	return zap.Any(field.Key, field.Value)
+}
+
+type groupMarshaler []lg.Field
+
+func (g groupMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range fieldsToZap(g) {
+		field.AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// filterCore writes the entries matching lg.SinkFilter to the wrapped core.
+// Fields of the logger and of the entry are matched, except those added
+// after a namespace.
+type filterCore struct {
+	zapcore.Core
+	filter     *lg.SinkFilter
+	fields     []zapcore.Field
+	namespaced bool
+}
+
+// newFilterCore returns core itself when filter is nil.
+func newFilterCore(core zapcore.Core, filter *lg.SinkFilter) zapcore.Core {
+	if filter == nil {
+		return core
+	}
+	return &filterCore{Core: core, filter: filter}
+}
+
+func (c *filterCore) With(fields []zapcore.Field) zapcore.Core {
+	derived := *c
+	derived.Core = c.Core.With(fields)
+	for _, field := range fields {
+		if derived.namespaced {
+			break
+		}
+		if field.Type == zapcore.NamespaceType {
+			derived.namespaced = true
+			break
+		}
+		derived.fields = append(derived.fields[:len(derived.fields):len(derived.fields)], field)
+	}
+	return &derived
+}
+
+func (c *filterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *filterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	lookup := func(key string) (string, bool) {
+		if !c.namespaced {
+			for _, field := range fields {
+				if field.Type == zapcore.NamespaceType {
+					break
+				}
+				if field.Key == key {
+					return fieldString(field), true
+				}
+			}
+		}
+		for i := len(c.fields) - 1; i >= 0; i-- {
+			if c.fields[i].Key == key {
+				return fieldString(c.fields[i]), true
+			}
+		}
+		return "", false
+	}
+
+	if !c.filter.Match(ent.Message, lookup) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+var jsonPool = buffer.NewPool()
+
+// jsonEncoder writes the timestamp before the level like the slog backend,
+// the zap JSON encoder writing the level first.
+type jsonEncoder struct {
+	zapcore.Encoder
+}
+
+// newJSONEncoder returns a JSON encoder of cfg writing TimeKey and LevelKey
+// first, they are encoded with lg.TimeLayout and in lowercase.
+func newJSONEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	cfg.TimeKey, cfg.LevelKey = "", ""
+	return &jsonEncoder{Encoder: zapcore.NewJSONEncoder(cfg)}
+}
+
+func (e *jsonEncoder) Clone() zapcore.Encoder {
+	return &jsonEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *jsonEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer line.Free()
+
+	buf := jsonPool.Get()
+	buf.AppendString(`{"` + lg.TimeKey + `":"`)
+	buf.AppendTime(ent.Time, lg.TimeLayout)
+	buf.AppendString(`","` + lg.LevelKey + `":"`)
+	buf.AppendString(ent.Level.String())
+	buf.AppendByte('"')
+	rest := line.Bytes()[1:]
+	if len(rest) > 0 && rest[0] != '}' {
+		buf.AppendByte(',')
+	}
+	_, _ = buf.Write(rest)
+	return buf, nil
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// floorLevel is the core level: the lowest of the factory level and
+// the level rules, so that ZapLoggers matched by a rule can log below LogLevel.
+// Each ZapLogger then applies its own level.
+type floorLevel struct {
+	level zap.AtomicLevel
+	rules []zapcore.Level
+}
+
+func (f *floorLevel) Level() zapcore.Level {
+	level := f.level.Level()
+	for _, rule := range f.rules {
+		level = min(level, rule)
+	}
+	return level
+}
+
+func (f *floorLevel) Enabled(level zapcore.Level) bool {
+	return level >= f.Level()
+}
+
+// sinkLevel raises the core level of a sink to its own minimal level.
+type sinkLevel struct {
+	level zapcore.LevelEnabler
+	min   zapcore.Level
+}
+
+func (s *sinkLevel) Enabled(level zapcore.Level) bool {
+	return level >= s.min && s.level.Enabled(level)
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder writes entries as logfmt key=value pairs. Objects and
+// namespaces are flattened into dotted keys, arrays are rendered as JSON and
+// stack traces as multiline strings.
+type logfmtEncoder struct {
+	cfg    zapcore.EncoderConfig
+	buf    *buffer.Buffer
+	prefix string
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{cfg: cfg, buf: logfmtPool.Get()}
+}
+
+func (e *logfmtEncoder) appendKey(key string) {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+	e.buf.Write(lg.AppendLogfmtKey(nil, e.prefix+key))
+	e.buf.AppendByte('=')
+}
+
+func (e *logfmtEncoder) appendValue(value string) {
+	e.buf.Write(lg.AppendLogfmtValue(nil, value))
+}
+
+func (e *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	enc := zapcore.NewMapObjectEncoder()
+	if err := enc.AddArray(key, arr); err != nil {
+		return err
+	}
+	return e.AddReflected(key, enc.Fields[key])
+}
+
+func (e *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	if stack, ok := obj.(stackMarshaler); ok {
+		e.AddString(key, lg.Stack(stack).String())
+		return nil
+	}
+	prefix := e.prefix
+	e.prefix += key + "."
+	err := obj.MarshalLogObject(e)
+	e.prefix = prefix
+	return err
+}
+
+func (e *logfmtEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *logfmtEncoder) AddBool(key string, value bool) {
+	e.appendKey(key)
+	e.buf.AppendBool(value)
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, value complex128) {
+	e.AddString(key, fmt.Sprint(value))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, value complex64) {
+	e.AddString(key, fmt.Sprint(value))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	e.AddString(key, value.String())
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, value float64) {
+	e.appendKey(key)
+	e.buf.AppendFloat(value, 64)
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, value float32) {
+	e.appendKey(key)
+	e.buf.AppendFloat(float64(value), 32)
+}
+
+func (e *logfmtEncoder) AddInt(key string, value int)     { e.AddInt64(key, int64(value)) }
+func (e *logfmtEncoder) AddInt32(key string, value int32) { e.AddInt64(key, int64(value)) }
+func (e *logfmtEncoder) AddInt16(key string, value int16) { e.AddInt64(key, int64(value)) }
+func (e *logfmtEncoder) AddInt8(key string, value int8)   { e.AddInt64(key, int64(value)) }
+
+func (e *logfmtEncoder) AddInt64(key string, value int64) {
+	e.appendKey(key)
+	e.buf.AppendInt(value)
+}
+
+func (e *logfmtEncoder) AddString(key, value string) {
+	e.appendKey(key)
+	e.appendValue(value)
+}
+
+func (e *logfmtEncoder) AddTime(key string, value time.Time) {
+	e.AddString(key, value.Format(lg.TimeLayout))
+}
+
+func (e *logfmtEncoder) AddUint(key string, value uint)       { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUint32(key string, value uint32)   { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUint16(key string, value uint16)   { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUint8(key string, value uint8)     { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUintptr(key string, value uintptr) { e.AddUint64(key, uint64(value)) }
+
+func (e *logfmtEncoder) AddUint64(key string, value uint64) {
+	e.appendKey(key)
+	e.buf.AppendUint(value)
+}
+
+// AddReflected renders value like the logfmt handler of the slog backend:
+// errors, fmt.Stringer and encoding.TextMarshaler as text, other values as
+// JSON.
+func (e *logfmtEncoder) AddReflected(key string, value any) error {
+	switch v := value.(type) {
+	case error:
+		e.AddString(key, v.Error())
+		return nil
+	case fmt.Stringer:
+		e.AddString(key, v.String())
+		return nil
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			e.AddString(key, string(text))
+			return nil
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	e.AddString(key, string(b))
+	return nil
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.prefix += key + "."
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{cfg: e.cfg, buf: logfmtPool.Get(), prefix: e.prefix}
+	clone.buf.Write(e.buf.Bytes())
+	return clone
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{cfg: e.cfg, buf: logfmtPool.Get()}
+
+	if e.cfg.TimeKey != "" {
+		final.AddTime(e.cfg.TimeKey, ent.Time)
+	}
+	if e.cfg.LevelKey != "" {
+		final.AddString(e.cfg.LevelKey, ent.Level.String())
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		final.AddString(e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && ent.Caller.Defined {
+		final.AddString(e.cfg.CallerKey, ent.Caller.TrimmedPath())
+	}
+	if e.cfg.MessageKey != "" {
+		final.AddString(e.cfg.MessageKey, ent.Message)
+	}
+
+	if e.buf.Len() > 0 {
+		final.buf.AppendByte(' ')
+		final.buf.Write(e.buf.Bytes())
+	}
+	final.prefix = e.prefix
+	for _, field := range fields {
+		field.AddTo(final)
+	}
+	final.prefix = ""
+
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		final.AddString(e.cfg.StacktraceKey, ent.Stack)
+	}
+	final.buf.AppendString(zapcore.DefaultLineEnding)
+	return final.buf, nil
+}
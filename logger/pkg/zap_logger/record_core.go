@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"maps"
+	"slices"
+
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// recordCore passes entries to a structured sink as lg.Record. Objects and
+// namespaces become group fields.
+type recordCore struct {
+	zapcore.LevelEnabler
+	w      lg.RecordWriter
+	fields []zapcore.Field
+}
+
+func newRecordCore(w lg.RecordWriter, level zapcore.LevelEnabler) zapcore.Core {
+	return &recordCore{LevelEnabler: level, w: w}
+}
+
+func (c *recordCore) With(fields []zapcore.Field) zapcore.Core {
+	derived := *c
+	derived.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &derived
+}
+
+func (c *recordCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *recordCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.w.WriteRecord(lg.Record{
+		Time:       ent.Time,
+		Severity:   recordSeverity(ent.Level),
+		Level:      ent.Level.CapitalString(),
+		Message:    ent.Message,
+		PC:         ent.Caller.PC,
+		Attributes: appendRecordFields(nil, append(c.fields[:len(c.fields):len(c.fields)], fields...)),
+	})
+}
+
+// Sync waits until the entries written before it are delivered.
+func (c *recordCore) Sync() error {
+	return c.w.Flush()
+}
+
+// appendRecordFields converts fields with the values zap encodes into maps,
+// the fields following a namespace are nested in it.
+func appendRecordFields(attrs []lg.Field, fields []zapcore.Field) []lg.Field {
+	for i, field := range fields {
+		if stack, ok := field.Interface.(stackMarshaler); ok {
+			attrs = append(attrs, lg.Any(field.Key, lg.Stack(stack)))
+			continue
+		}
+		if field.Type == zapcore.NamespaceType {
+			if nested := appendRecordFields(nil, fields[i+1:]); len(nested) > 0 {
+				attrs = append(attrs, lg.Group(field.Key, nested...))
+			}
+			return attrs
+		}
+
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+		for _, key := range slices.Sorted(maps.Keys(enc.Fields)) {
+			attrs = append(attrs, lg.Any(key, enc.Fields[key]))
+		}
+	}
+	return attrs
+}
+
+func recordSeverity(level zapcore.Level) int32 {
+	switch level {
+	case zapcore.DebugLevel:
+		return lg.SeverityDebug
+	case zapcore.InfoLevel:
+		return lg.SeverityInfo
+	case zapcore.WarnLevel:
+		return lg.SeverityWarn
+	case zapcore.ErrorLevel:
+		return lg.SeverityError
+	case zapcore.DPanicLevel:
+		return lg.SeverityError + 1
+	case zapcore.PanicLevel:
+		return lg.SeverityError + 2
+	default:
+		return lg.SeverityFatal
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// redactCore applies lg.Redaction to the fields of entries and loggers.
+type redactCore struct {
+	zapcore.Core
+	redaction *lg.Redaction
+}
+
+func newRedactCore(core zapcore.Core, redaction *lg.Redaction) zapcore.Core {
+	return &redactCore{Core: core, redaction: redaction}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redactFields(fields)), redaction: c.redaction}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.redactFields(fields))
+}
+
+func (c *redactCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = c.redactField(field)
+	}
+	return redacted
+}
+
+func (c *redactCore) redactField(field zapcore.Field) zapcore.Field {
+	if field.Key == lg.StacktraceKey {
+		return field
+	}
+	if redactor, ok := field.Interface.(lg.Redactor); ok {
+		field = zap.Any(field.Key, redactor.Redact())
+	}
+
+	if strategy, ok := c.redaction.MatchKey(field.Key); ok {
+		return zap.String(field.Key, lg.Conceal(strategy, fieldString(field)))
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = c.redaction.String(field.String)
+	case zapcore.ObjectMarshalerType:
+		if group, ok := field.Interface.(groupMarshaler); ok {
+			field.Interface = groupMarshaler(c.redaction.Fields(group))
+			break
+		}
+		return c.redactRendered(field)
+	case zapcore.ErrorType, zapcore.StringerType, zapcore.ReflectType, zapcore.ArrayMarshalerType:
+		return c.redactRendered(field)
+	}
+	return field
+}
+
+// redactRendered replaces field with its redacted rendering when a value rule
+// matches it, see lg.Redaction.Render.
+func (c *redactCore) redactRendered(field zapcore.Field) zapcore.Field {
+	if redacted, ok := c.redaction.Render(field.Interface); ok {
+		return zap.String(field.Key, redacted)
+	}
+	return field
+}
+
+// fieldString renders the value of field for hashing.
+func fieldString(field zapcore.Field) string {
+	if field.Type == zapcore.StringType {
+		return field.String
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	return fmt.Sprint(enc.Fields[field.Key])
+}
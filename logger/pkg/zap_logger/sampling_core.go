@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// samplingCore drops entries rejected by lg.Sampler and logs the number of
+// dropped entries through the wrapped core.
+type samplingCore struct {
+	zapcore.Core
+	sampler *lg.Sampler
+}
+
+// newSamplingCore starts the dropped entries reporting of sampler, stop it
+// with sampler.Close. Reports are checked against the level of every sink
+// like other entries.
+func newSamplingCore(core zapcore.Core, sampler *lg.Sampler) zapcore.Core {
+	sampler.Start(func(fields []lg.Field) {
+		ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), Message: lg.MsgRecordsDropped}
+		core.Check(ent, nil).Write(fieldsToZap(fields)...)
+	})
+	return &samplingCore{Core: core, sampler: sampler}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) || !c.sampler.Sample(ent.Level.String(), ent.Message) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
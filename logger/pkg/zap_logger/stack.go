@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+)
+
+// stackMarshaler renders lg.Stack like the slog backend: an object of frame
+// objects keyed by their index.
+type stackMarshaler lg.Stack
+
+func (s stackMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for i, frame := range s {
+		if err := enc.AddObject(strconv.Itoa(i), frameMarshaler(frame)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type frameMarshaler lg.Frame
+
+func (f frameMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("function", f.Function)
+	enc.AddString("file", f.File)
+	enc.AddInt("line", f.Line)
+	return nil
+}
+
+func stackField(stack lg.Stack) zap.Field {
+	return zap.Object(lg.StacktraceKey, stackMarshaler(stack))
+}
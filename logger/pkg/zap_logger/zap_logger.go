@@ -3,109 +3,200 @@ package logger
 import (
 	"context"
 	"fmt"
-	"os"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	olg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/otlp_logger"
 )
 
-const (
-	unknownFunctionName  = "unknown function name"
-	serviceNameSeparator = " - "
-	funcNameSeparator    = ": "
-)
+const funcNameSeparator = ": "
 
-type SQLErrorType int
+type SQLErrorType = lg.SQLErrorType
 
 const (
-	SQLSelect SQLErrorType = iota
-	SQLInsert
-	SQLUpdate
-	SQLDelete
+	SQLSelect     = lg.SQLSelect
+	SQLInsert     = lg.SQLInsert
+	SQLUpdate     = lg.SQLUpdate
+	SQLDelete     = lg.SQLDelete
+	SQLBegin      = lg.SQLBegin
+	SQLCommit     = lg.SQLCommit
+	SQLRollback   = lg.SQLRollback
+	SQLDDL        = lg.SQLDDL
+	SQLMigration  = lg.SQLMigration
+	SQLUpsert     = lg.SQLUpsert
+	SQLConnection = lg.SQLConnection
 )
 
 type ZapLogger struct {
-	zapLog       *zap.Logger
-	functionName string
+	zapLog        *zap.Logger
+	level         zapcore.LevelEnabler
+	functionName  string
+	ctxFields     []zap.Field
+	start         time.Time
+	slowThreshold time.Duration
+	withStack     bool
+	callerSkip    int
+	// origin is the program counter the logger was created at, see lg.CallerPC.
+	origin uintptr
 }
 
 type ZapLoggerFactory struct {
-	zapLog *zap.Logger
-	config Config
+	zapLog      *zap.Logger
+	level       zap.AtomicLevel
+	rules       lg.LevelRules
+	ruleLevels  []zapcore.Level
+	config      Config
+	outputs     *lg.Outputs
+	sampler     *lg.Sampler
+	stopWatcher func()
 }
 
-type Config struct {
-	ServiceName string
-	Version     string
-	LogLevel    string
-	OutputPath  string
-}
+type Config = lg.Config
 
 var (
-	consoleEncoderConfig = zapcore.EncoderConfig{
-		TimeKey:        "time",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalColorLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-	}
-
 	jsonEncoderConfig = zapcore.EncoderConfig{
-		TimeKey:        "timestamp",
-		LevelKey:       "level",
+		TimeKey:        lg.TimeKey,
+		LevelKey:       lg.LevelKey,
 		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "message",
-		StacktraceKey:  "stacktrace",
+		CallerKey:      lg.CallerKey,
+		MessageKey:     lg.MessageKey,
+		StacktraceKey:  lg.StacktraceKey,
 		LineEnding:     zapcore.DefaultLineEnding,
 		EncodeLevel:    zapcore.LowercaseLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.MillisDurationEncoder,
+		EncodeTime:     zapcore.TimeEncoderOfLayout(lg.TimeLayout),
+		EncodeDuration: millisDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 )
 
 func NewZapLoggerFactory(cfg Config) (*ZapLoggerFactory, error) {
-	zapLog, err := newZapLogger(cfg)
+	logLevel, err := parseZapLogLevel(cfg.LogLevel)
 	if err != nil {
 		return nil, err
 	}
+	level := zap.NewAtomicLevelAt(logLevel)
 
-	return &ZapLoggerFactory{
-		zapLog: zapLog,
-		config: cfg,
-	}, nil
+	rules, err := lg.ParseLevelRules(cfg.LevelRules)
+	if err != nil {
+		return nil, err
+	}
+	ruleLevels := make([]zapcore.Level, len(rules))
+	for i, rule := range rules {
+		if ruleLevels[i], err = lookupZapLogLevel(rule.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	sampler, err := lg.NewSampler(cfg.Sampling)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := lg.NewOutputs(cfg)
+	zapLog, err := newZapLogger(cfg, &floorLevel{level: level, rules: ruleLevels}, sampler, outputs)
+	if err != nil {
+		_ = outputs.Close()
+		return nil, err
+	}
+
+	factory := &ZapLoggerFactory{
+		zapLog:     zapLog,
+		level:      level,
+		rules:      rules,
+		ruleLevels: ruleLevels,
+		config:     cfg,
+		outputs:    outputs,
+		sampler:    sampler,
+	}
+
+	if cfg.ReopenOnSIGHUP && outputs.HasFiles() {
+		factory.stopWatcher = lg.ReopenOnSignal(factory, func(err error) {
+			zapLog.Error(lg.MsgReopenFailed, fieldsToZap(lg.EncodeError(err))...)
+		})
+	}
+
+	return factory, nil
 }
 
-func (f *ZapLoggerFactory) GetLogger(_ context.Context, fields ...zap.Field) *ZapLogger {
+func (f *ZapLoggerFactory) GetLogger(ctx context.Context, fields ...zap.Field) *ZapLogger {
+	pc := f.callerPC()
+	return f.newLogger(ctx, pc, lg.FunctionName(pc), fields)
+}
+
+// callerPC returns the program counter of the code calling a public factory
+// method, it must be called directly by that method.
+func (f *ZapLoggerFactory) callerPC() uintptr {
+	return lg.CallerPC(2+f.config.CallerSkip, 0)
+}
+
+func (f *ZapLoggerFactory) newLogger(ctx context.Context, pc uintptr, fullFunctionName string, fields []zap.Field) *ZapLogger {
 	msg := lg.MsgStart
 	if len(fields) > 0 {
 		msg = lg.MsgStartWithParams
 	}
 
-	functionName := getFunctionName(1, true)
+	var level zapcore.LevelEnabler = f.level
+	if idx := f.rules.Match(fullFunctionName); idx != -1 {
+		level = f.ruleLevels[idx]
+	}
 
 	logger := &ZapLogger{
-		zapLog:       f.zapLog,
-		functionName: functionName,
+		zapLog:        f.zapLog,
+		level:         level,
+		functionName:  shortenFunctionName(fullFunctionName),
+		ctxFields:     fieldsToZap(lg.ExtractContextFields(ctx, f.config.ContextExtractors)),
+		start:         time.Now(),
+		slowThreshold: f.config.SlowCallThreshold,
+		withStack:     f.config.StacktraceOnError,
+		callerSkip:    f.config.CallerSkip,
+		origin:        pc,
 	}
+	logger.logMessage(pc, zapcore.InfoLevel, msg, fields)
+	return logger
+}
 
-	logger.zapLog.Info(createMessageWithFuncName(functionName, msg), fields...)
+// Level returns the current minimal level of the factory loggers.
+func (f *ZapLoggerFactory) Level() string {
+	return f.level.Level().String()
+}
 
-	return logger
+// SetLevel changes the minimal level of all loggers created by the factory,
+// except those matched by a level rule, which keep the level of the rule.
+func (f *ZapLoggerFactory) SetLevel(level string) error {
+	logLevel, err := lookupZapLogLevel(level)
+	if err != nil {
+		return err
+	}
+	f.level.SetLevel(logLevel)
+	return nil
+}
+
+// Reopen reopens the log files, see lg.RotatingFile.Reopen.
+func (f *ZapLoggerFactory) Reopen() error {
+	return f.outputs.Reopen()
+}
+
+// AsyncStats returns the queue metrics of the sinks when Config.Async is set.
+func (f *ZapLoggerFactory) AsyncStats() []lg.AsyncStats {
+	return f.outputs.AsyncStats()
 }
 
 func (f *ZapLoggerFactory) Close() error {
-	return f.zapLog.Sync()
+	if f.stopWatcher != nil {
+		f.stopWatcher()
+	}
+	f.sampler.Close()
+	err := f.zapLog.Sync()
+	if closeErr := f.outputs.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 func createMessageWithFuncName(funcName, msg string) string {
@@ -115,48 +206,35 @@ func createMessageWithFuncName(funcName, msg string) string {
 	return funcName + funcNameSeparator + msg
 }
 
-func getFunctionName(skippedStackFrames int, shortFunctionName bool) string {
-	pc, _, _, ok := runtime.Caller(skippedStackFrames + 1)
-	if !ok {
-		return unknownFunctionName
+func shortenFunctionName(funcName string) string {
+	if idx := strings.LastIndex(funcName, "/"); idx != -1 {
+		funcName = funcName[idx+1:]
 	}
-
-	funcName := runtime.FuncForPC(pc).Name()
-
-	if shortFunctionName {
-		if idx := strings.LastIndex(funcName, "/"); idx != -1 {
-			funcName = funcName[idx+1:]
-		}
-		if idx := strings.LastIndex(funcName, "."); idx != -1 {
-			funcName = funcName[idx+1:]
-		}
+	if idx := strings.LastIndex(funcName, "."); idx != -1 {
+		funcName = funcName[idx+1:]
 	}
-
 	return funcName
 }
 
-func customEncodeCaller(serviceName, version string) zapcore.CallerEncoder {
-	var prefix string
-	switch {
-	case serviceName == "" && version != "":
-		prefix = version
-	case serviceName != "" && version != "":
-		prefix = serviceName + " " + version
-	case serviceName != "":
-		prefix = serviceName
-	default:
-		return zapcore.ShortCallerEncoder
-	}
-
-	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(prefix + serviceNameSeparator + caller.TrimmedPath())
-	}
+// millisDurationEncoder writes fractional milliseconds like the slog backend.
+func millisDurationEncoder(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendFloat64(float64(d) / float64(time.Millisecond))
 }
 
+// parseZapLogLevel parses Config.LogLevel, debug when empty.
 func parseZapLogLevel(level string) (zapcore.Level, error) {
 	if level == "" {
 		return zapcore.DebugLevel, nil
 	}
+	return lookupZapLogLevel(level)
+}
+
+// lookupZapLogLevel parses the levels of SetLevel, sinks and rules, which
+// must not be empty: zapcore.ParseLevel reads an empty level as info.
+func lookupZapLogLevel(level string) (zapcore.Level, error) {
+	if level == "" {
+		return zapcore.DebugLevel, lg.ErrEmptyLevel
+	}
 	minLogLevel, err := zapcore.ParseLevel(level)
 	if err != nil {
 		return zapcore.DebugLevel, err
@@ -164,134 +242,332 @@ func parseZapLogLevel(level string) (zapcore.Level, error) {
 	return minLogLevel, nil
 }
 
-func newZapLogger(cfg Config) (*zap.Logger, error) {
-	logLevel, err := parseZapLogLevel(cfg.LogLevel)
+func newZapLogger(cfg Config, logLevel zapcore.LevelEnabler, sampler *lg.Sampler, outputs *lg.Outputs) (*zap.Logger, error) {
+	redaction, err := lg.NewRedaction(cfg.RedactRules)
 	if err != nil {
 		return nil, err
 	}
 
-	consoleCfg := consoleEncoderConfig
-	consoleCfg.EncodeCaller = customEncodeCaller(cfg.ServiceName, cfg.Version)
+	sinks, err := cfg.SinkConfigs()
+	if err != nil {
+		return nil, err
+	}
 
-	consoleEncoder := zapcore.NewConsoleEncoder(consoleCfg)
-	jsonEncoder := zapcore.NewJSONEncoder(jsonEncoderConfig)
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		core, err := newSinkCore(cfg, sink, logLevel, redaction, outputs)
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, core)
+	}
+
+	var core zapcore.Core
+	if cfg.Failover == nil {
+		core = zapcore.NewTee(cores...)
+	} else if core, err = newFailoverTee(cfg, sinks, cores, logLevel, redaction, outputs); err != nil {
+		return nil, err
+	}
+	if sampler != nil {
+		core = newSamplingCore(core, sampler)
+	}
 
-	cores := []zapcore.Core{
-		zapcore.NewCore(
-			consoleEncoder,
-			zapcore.Lock(os.Stderr),
-			zap.NewAtomicLevelAt(logLevel),
-		),
+	return zap.New(core), nil
+}
+
+// newFailoverTee isolates the sink cores with the options of cfg.Failover.
+func newFailoverTee(cfg Config, sinks []lg.SinkConfig, cores []zapcore.Core, logLevel zapcore.LevelEnabler, redaction *lg.Redaction, outputs *lg.Outputs) (zapcore.Core, error) {
+	names := make([]string, len(sinks))
+	for i, sink := range sinks {
+		names[i] = sink.Destination
+	}
+
+	fallbackConfig, err := cfg.FallbackConfig()
+	if err != nil {
+		return nil, err
+	}
+	var fallback zapcore.Core
+	if fallbackConfig != nil {
+		if fallback, err = newSinkCore(cfg, *fallbackConfig, logLevel, redaction, outputs); err != nil {
+			return nil, err
+		}
 	}
+	return newFailoverCore(*cfg.Failover, cores, names, fallback), nil
+}
 
-	if cfg.OutputPath != "" {
-		file, err := os.OpenFile(cfg.OutputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+func newSinkCore(cfg Config, sink lg.SinkConfig, logLevel zapcore.LevelEnabler, redaction *lg.Redaction, outputs *lg.Outputs) (zapcore.Core, error) {
+	if sink.Level != "" {
+		sinkMin, err := lookupZapLogLevel(sink.Level)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open log file: %w", err)
+			return nil, err
 		}
+		logLevel = &sinkLevel{level: logLevel, min: sinkMin}
+	}
 
-		fileCore := zapcore.NewCore(
-			jsonEncoder,
-			zapcore.AddSync(file),
-			zap.NewAtomicLevelAt(logLevel),
-		)
-		cores = append(cores, fileCore)
+	filter, err := lg.NewSinkFilter(sink.Include, sink.Exclude)
+	if err != nil {
+		return nil, err
 	}
 
-	core := zapcore.NewTee(cores...)
+	if sink.IsStructured() {
+		w, err := openRecordWriter(cfg, sink, outputs)
+		if err != nil {
+			return nil, err
+		}
+		return newFilterCore(newRedactCore(newRecordCore(w, logLevel), redaction), filter), nil
+	}
 
-	return zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1)), nil
+	w, err := outputs.Open(sink.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoder zapcore.Encoder
+	switch sink.Format {
+	case lg.FormatJSON:
+		encoder = newJSONEncoder(jsonEncoderConfig)
+	case lg.FormatLogfmt:
+		encoder = newLogfmtEncoder(jsonEncoderConfig)
+	default:
+		encoder = newConsoleEncoder(outputs.Colored(sink), lg.CallerPrefix(cfg.ServiceName, cfg.Version))
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.Lock(zapcore.AddSync(w)), logLevel)
+	if sink.Format != lg.FormatText {
+		core = core.With(fieldsToZap(lg.ServiceFields(cfg)))
+	}
+	return newFilterCore(newRedactCore(core, redaction), filter), nil
 }
 
-func (z *ZapLogger) WithFields(fields ...zap.Field) *ZapLogger {
-	return &ZapLogger{
-		zapLog:       z.zapLog.With(fields...),
-		functionName: z.functionName,
+func openRecordWriter(cfg Config, sink lg.SinkConfig, outputs *lg.Outputs) (lg.RecordWriter, error) {
+	if !sink.IsOTLP() {
+		return outputs.OpenRecordWriter(sink)
 	}
+	exporter, err := olg.NewExporter(*sink.OTLP, cfg.ServiceName, cfg.Version)
+	if err != nil {
+		return nil, err
+	}
+	outputs.Track(exporter)
+	return exporter, nil
+}
+
+func (z *ZapLogger) WithFields(fields ...zap.Field) *ZapLogger {
+	derived := *z
+	derived.zapLog = z.zapLog.With(fields...)
+	derived.ctxFields = withoutKeys(z.ctxFields, fields)
+	return &derived
+}
+
+// WithCallerSkip returns a logger attributing records to the code skip
+// frames above its usual caller, for libraries wrapping the logger.
+func (z *ZapLogger) WithCallerSkip(skip int) *ZapLogger {
+	derived := *z
+	derived.callerSkip += skip
+	return &derived
 }
 
 func (z *ZapLogger) Debug(msg string, fields ...zap.Field) {
-	if z.zapLog.Core().Enabled(zapcore.DebugLevel) {
-		z.zapLog.Debug(createMessageWithFuncName(z.functionName, msg), fields...)
-	}
+	z.logMessage(z.callerPC(), zapcore.DebugLevel, msg, fields)
 }
 
 func (z *ZapLogger) Info(msg string, fields ...zap.Field) {
-	if z.zapLog.Core().Enabled(zapcore.InfoLevel) {
-		z.zapLog.Info(createMessageWithFuncName(z.functionName, msg), fields...)
-	}
+	z.logMessage(z.callerPC(), zapcore.InfoLevel, msg, fields)
 }
 
 func (z *ZapLogger) Warning(msg string, fields ...zap.Field) {
-	if z.zapLog.Core().Enabled(zapcore.WarnLevel) {
-		z.zapLog.Warn(createMessageWithFuncName(z.functionName, msg), fields...)
-	}
+	z.logMessage(z.callerPC(), zapcore.WarnLevel, msg, fields)
 }
 
 func (z *ZapLogger) Error(msg string, fields ...zap.Field) {
-	if z.zapLog.Core().Enabled(zapcore.ErrorLevel) {
-		z.zapLog.Error(createMessageWithFuncName(z.functionName, msg), fields...)
-	}
+	z.logMessage(z.callerPC(), zapcore.ErrorLevel, msg, fields)
 }
 
 func (z *ZapLogger) ErrorIn(funcName string, err error, fields ...zap.Field) {
-	msg := fmt.Sprintf(lg.MsgCompletesWithError, funcName)
-	allFields := append(fields, zap.Error(err))
-	z.zapLog.Error(createMessageWithFuncName(z.functionName, msg), allFields...)
+	z.errorIn(z.callerPC(), funcName, err, fields)
 }
 
 func (z *ZapLogger) ErrorSQL(operation SQLErrorType, table string, err error, fields ...zap.Field) {
-	var msg string
-	switch operation {
-	case SQLSelect:
-		msg = fmt.Sprintf(lg.MsgSQLSelectWithError, table)
-	case SQLInsert:
-		msg = fmt.Sprintf(lg.MsgSQLInsertWithError, table)
-	case SQLUpdate:
-		msg = fmt.Sprintf(lg.MsgSQLUpdateWithError, table)
-	case SQLDelete:
-		msg = fmt.Sprintf(lg.MsgSQLDeleteWithError, table)
-	default:
-		msg = fmt.Sprintf("SQL operation error on table %s", table)
-	}
-
-	allFields := append(fields, zap.Error(err))
-	z.zapLog.Error(createMessageWithFuncName(z.functionName, msg), allFields...)
+	z.errorSQL(z.callerPC(), operation, table, err, fields)
 }
 
 func (z *ZapLogger) ErrorSQLSelect(table string, err error, fields ...zap.Field) {
-	z.ErrorSQL(SQLSelect, table, err, fields...)
+	z.errorSQL(z.callerPC(), SQLSelect, table, err, fields)
 }
 
 func (z *ZapLogger) ErrorSQLInsert(table string, err error, fields ...zap.Field) {
-	z.ErrorSQL(SQLInsert, table, err, fields...)
+	z.errorSQL(z.callerPC(), SQLInsert, table, err, fields)
 }
 
 func (z *ZapLogger) ErrorSQLUpdate(table string, err error, fields ...zap.Field) {
-	z.ErrorSQL(SQLUpdate, table, err, fields...)
+	z.errorSQL(z.callerPC(), SQLUpdate, table, err, fields)
 }
 
 func (z *ZapLogger) ErrorSQLDelete(table string, err error, fields ...zap.Field) {
-	z.ErrorSQL(SQLDelete, table, err, fields...)
+	z.errorSQL(z.callerPC(), SQLDelete, table, err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLBegin(err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLBegin, "", err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLCommit(err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLCommit, "", err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLRollback(err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLRollback, "", err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLDDL(table string, err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLDDL, table, err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLMigration(migration string, err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLMigration, migration, err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLUpsert(table string, err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLUpsert, table, err, fields)
+}
+
+func (z *ZapLogger) ErrorSQLConnection(err error, fields ...zap.Field) {
+	z.errorSQL(z.callerPC(), SQLConnection, "", err, fields)
 }
 
 func (z *ZapLogger) Panic(msg string, fields ...zap.Field) {
-	z.zapLog.Panic(createMessageWithFuncName(z.functionName, msg), fields...)
+	z.log(z.callerPC(), zapcore.PanicLevel, createMessageWithFuncName(z.functionName, msg), z.withContext(fields))
 }
 
 func (z *ZapLogger) Fatal(msg string, fields ...zap.Field) {
-	z.zapLog.Fatal(createMessageWithFuncName(z.functionName, msg), fields...)
+	z.log(z.callerPC(), zapcore.FatalLevel, createMessageWithFuncName(z.functionName, msg), z.withContext(fields))
 }
 
 func (z *ZapLogger) End() {
-	if err := recover(); err != nil {
-		z.zapLog.Error(lg.MsgPanicWasCatched,
-			zap.Any("error", err),
-			zap.Stack("stacktrace"))
+	z.end(z.callerPC(), recover(), nil)
+}
 
-		z.zapLog.Info(createMessageWithFuncName(z.functionName, lg.MsgEnd))
-		panic(err)
+// EndWithError is End for functions with a named error result, use it as
+// defer logger.EndWithError(&err). A non-nil error is logged at Error level
+// with its wrapped chain.
+func (z *ZapLogger) EndWithError(errPtr *error) {
+	z.end(z.callerPC(), recover(), errPtr)
+}
+
+func (z *ZapLogger) errorIn(pc uintptr, funcName string, err error, fields []zap.Field) {
+	msg := fmt.Sprintf(lg.MsgCompletesWithError, funcName)
+	z.logMessage(pc, zapcore.ErrorLevel, msg, append(fields, fieldsToZap(lg.EncodeError(err))...))
+}
+
+func (z *ZapLogger) errorSQL(pc uintptr, operation SQLErrorType, table string, err error, fields []zap.Field) {
+	allFields := append(fields, fieldsToZap(lg.EncodeError(err))...)
+	allFields = append(allFields, fieldsToZap(lg.SQLFields(operation, table))...)
+	z.logMessage(pc, zapcore.ErrorLevel, lg.SQLErrorMessage(operation, table), allFields)
+}
+
+func (z *ZapLogger) end(pc uintptr, panicValue any, errPtr *error) {
+	if panicValue != nil {
+		if z.enabled(zapcore.ErrorLevel) {
+			z.log(pc, zapcore.ErrorLevel, lg.MsgPanicWasCatched, z.withContext([]zap.Field{
+				zap.Any("error", panicValue),
+				zap.String("panic_type", fmt.Sprintf("%T", panicValue)),
+				stackField(lg.CaptureStackFrom(pc)),
+			}))
+		}
+		z.logEnd(pc, nil)
+		panic(panicValue)
+	}
+
+	var err error
+	if errPtr != nil {
+		err = *errPtr
+	}
+	z.logEnd(pc, err)
+}
+
+func (z *ZapLogger) logEnd(pc uintptr, err error) {
+	duration := time.Since(z.start)
+
+	if err != nil {
+		fields := append(fieldsToZap(lg.EncodeError(err)), zap.Duration(lg.DurationKey, duration))
+		z.logMessage(pc, zapcore.ErrorLevel, lg.MsgEndWithError, fields)
+		return
+	}
+
+	if z.slowThreshold > 0 && duration > z.slowThreshold {
+		z.logMessage(pc, zapcore.WarnLevel, lg.MsgEnd, []zap.Field{
+			zap.Duration(lg.DurationKey, duration),
+			zap.Duration(lg.SlowThresholdKey, z.slowThreshold),
+		})
+		return
+	}
+
+	z.logMessage(pc, zapcore.InfoLevel, lg.MsgEnd, []zap.Field{zap.Duration(lg.DurationKey, duration)})
+}
+
+// callerPC returns the program counter of the code calling a public method,
+// it must be called directly by that method.
+func (z *ZapLogger) callerPC() uintptr {
+	return lg.CallerPC(2+z.callerSkip, z.origin)
+}
+
+// logMessage logs msg prefixed with the function name and the context fields
+// when level is enabled, adding the stack to Error level entries without one
+// when StacktraceOnError is set.
+func (z *ZapLogger) logMessage(pc uintptr, level zapcore.Level, msg string, fields []zap.Field) {
+	if !z.enabled(level) {
+		return
+	}
+	if z.withStack && level >= zapcore.ErrorLevel && !hasStack(fields) {
+		fields = append(fields, stackField(lg.CaptureStackFrom(pc)))
+	}
+	z.log(pc, level, createMessageWithFuncName(z.functionName, msg), z.withContext(fields))
+}
+
+func hasStack(fields []zap.Field) bool {
+	for _, field := range fields {
+		if field.Key == lg.StacktraceKey {
+			return true
+		}
+	}
+	return false
+}
+
+// log writes an entry attributed to pc, the caller captured at the public
+// method, instead of the caller zap would find at a fixed depth.
+func (z *ZapLogger) log(pc uintptr, level zapcore.Level, msg string, fields []zap.Field) {
+	ce := z.zapLog.Check(level, msg)
+	if ce == nil {
+		return
 	}
+	if pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		ce.Caller = zapcore.NewEntryCaller(pc, frame.File, frame.Line, true)
+		ce.Caller.Function = frame.Function
+	}
+	ce.Write(fields...)
+}
+
+// withContext appends context fields not overridden by fields.
+func (z *ZapLogger) withContext(fields []zap.Field) []zap.Field {
+	if len(z.ctxFields) == 0 {
+		return fields
+	}
+	return slices.Concat(fields, withoutKeys(z.ctxFields, fields))
+}
+
+func withoutKeys(fields []zap.Field, override []zap.Field) []zap.Field {
+	if len(fields) == 0 || len(override) == 0 {
+		return fields
+	}
+	kept := make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		if !slices.ContainsFunc(override, func(o zap.Field) bool { return o.Key == field.Key }) {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
 
-	z.zapLog.Info(createMessageWithFuncName(z.functionName, lg.MsgEnd))
+func (z *ZapLogger) enabled(level zapcore.Level) bool {
+	return z.level.Enabled(level) && z.zapLog.Core().Enabled(level)
 }
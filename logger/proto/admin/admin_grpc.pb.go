@@ -0,0 +1,168 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: proto/admin/admin.proto
+
+package admin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LevelAdmin_GetLevel_FullMethodName = "/logger.admin.LevelAdmin/GetLevel"
+	LevelAdmin_SetLevel_FullMethodName = "/logger.admin.LevelAdmin/SetLevel"
+)
+
+// LevelAdminClient is the client API for LevelAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Service
+type LevelAdminClient interface {
+	// Receive current log level
+	GetLevel(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LevelReply, error)
+	// Send new log level and optional ttl after which the previous level is restored
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*LevelReply, error)
+}
+
+type levelAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLevelAdminClient(cc grpc.ClientConnInterface) LevelAdminClient {
+	return &levelAdminClient{cc}
+}
+
+func (c *levelAdminClient) GetLevel(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LevelReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LevelReply)
+	err := c.cc.Invoke(ctx, LevelAdmin_GetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelAdminClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*LevelReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LevelReply)
+	err := c.cc.Invoke(ctx, LevelAdmin_SetLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LevelAdminServer is the server API for LevelAdmin service.
+// All implementations must embed UnimplementedLevelAdminServer
+// for forward compatibility.
+//
+// Service
+type LevelAdminServer interface {
+	// Receive current log level
+	GetLevel(context.Context, *emptypb.Empty) (*LevelReply, error)
+	// Send new log level and optional ttl after which the previous level is restored
+	SetLevel(context.Context, *SetLevelRequest) (*LevelReply, error)
+	mustEmbedUnimplementedLevelAdminServer()
+}
+
+// UnimplementedLevelAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLevelAdminServer struct{}
+
+func (UnimplementedLevelAdminServer) GetLevel(context.Context, *emptypb.Empty) (*LevelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLevel not implemented")
+}
+func (UnimplementedLevelAdminServer) SetLevel(context.Context, *SetLevelRequest) (*LevelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLevel not implemented")
+}
+func (UnimplementedLevelAdminServer) mustEmbedUnimplementedLevelAdminServer() {}
+func (UnimplementedLevelAdminServer) testEmbeddedByValue()                    {}
+
+// UnsafeLevelAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LevelAdminServer will
+// result in compilation errors.
+type UnsafeLevelAdminServer interface {
+	mustEmbedUnimplementedLevelAdminServer()
+}
+
+func RegisterLevelAdminServer(s grpc.ServiceRegistrar, srv LevelAdminServer) {
+	// If the following call pancis, it indicates UnimplementedLevelAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LevelAdmin_ServiceDesc, srv)
+}
+
+func _LevelAdmin_GetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelAdminServer).GetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LevelAdmin_GetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelAdminServer).GetLevel(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelAdmin_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelAdminServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LevelAdmin_SetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelAdminServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LevelAdmin_ServiceDesc is the grpc.ServiceDesc for LevelAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LevelAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logger.admin.LevelAdmin",
+	HandlerType: (*LevelAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevel",
+			Handler:    _LevelAdmin_GetLevel_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LevelAdmin_SetLevel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/admin/admin.proto",
+}
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"os"
 
 	petname "github.com/dustinkirkland/golang-petname"
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	lgf "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/factory"
+	glg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/grpc_logger"
 	petnamepb "github.com/guryev-vladislav/go-toolkit/servers/grpc_server/petname/proto"
 	"github.com/ilyakaznacheev/cleanenv"
 	"google.golang.org/grpc"
@@ -18,7 +22,9 @@ import (
 )
 
 type Config struct {
-	GRPCPort string `yaml:"grpc_port" env:"PETNAME_GRPC_PORT" env-default:"28081"`
+	GRPCPort   string `yaml:"grpc_port" env:"PETNAME_GRPC_PORT" env-default:"28081"`
+	LogLevel   string `yaml:"log_level" env:"PETNAME_LOG_LEVEL" env-default:"info"`
+	LogBackend string `yaml:"log_backend" env:"PETNAME_LOG_BACKEND" env-default:"slog"`
 }
 
 type server struct {
@@ -69,31 +75,60 @@ func main() {
 
 	var cfg Config
 
+	configSource := "environment variables"
 	if configPath != "" {
 		if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 			log.Fatalf("failed to read config from file: %v", err)
 		}
-		log.Printf("Config loaded from file: %s", configPath)
+		configSource = configPath
 	} else {
 		if err := cleanenv.ReadEnv(&cfg); err != nil {
 			log.Fatalf("failed to read config from env: %v", err)
 		}
-		log.Printf("Config loaded from environment variables")
 	}
 
+	factory, err := lgf.NewFactory(lg.Config{
+		Backend:     cfg.LogBackend,
+		ServiceName: "petname",
+		LogLevel:    cfg.LogLevel,
+	})
+	if err != nil {
+		log.Fatalf("failed to create logger: %v", err)
+	}
+
+	err = run(factory, cfg, configSource)
+	factory.Close()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(factory lg.Factory, cfg Config, configSource string) error {
+	logger := factory.Get(context.Background())
+	defer logger.End()
+
+	logger.Info("config loaded", lg.String("source", configSource))
+
 	address := fmt.Sprintf("0.0.0.0:%s", cfg.GRPCPort)
 
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		logger.ErrorIn("net.Listen", err)
+		return err
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(glg.UnaryServerInterceptor(factory)),
+		grpc.ChainStreamInterceptor(glg.StreamServerInterceptor(factory)),
+	)
 	petnamepb.RegisterPetnameGeneratorServer(s, &server{})
 	reflection.Register(s)
 
-	log.Printf("Petname gRPC server starting on %s", address)
+	logger.Info("Petname gRPC server starting", lg.String("address", address))
 	if err := s.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+		logger.ErrorIn("Serve", err)
+		return err
 	}
+
+	return nil
 }
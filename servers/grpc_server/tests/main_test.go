@@ -0,0 +1,39 @@
+package grpc_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	lg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg"
+	lgf "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/factory"
+	glg "github.com/guryev-vladislav/digital-showcase/golang/lib/logger/pkg/grpc_logger"
+)
+
+var loggerFactory lg.Factory
+
+func TestMain(m *testing.M) {
+	factory, err := lgf.NewFactory(lg.Config{ServiceName: "tests", LogLevel: "debug"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+	loggerFactory = factory
+
+	code := m.Run()
+	factory.Close()
+	os.Exit(code)
+}
+
+// newClient creates a client that logs every call and propagates request ids to the server.
+func newClient(address string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(glg.UnaryClientInterceptor(loggerFactory)),
+		grpc.WithChainStreamInterceptor(glg.StreamClientInterceptor(loggerFactory)),
+	)
+}
@@ -10,9 +10,7 @@ import (
 
 	pb "github.com/guryev-vladislav/go-toolkit/servers/grpc_server/tests/proto/petname"
 	"github.com/stretchr/testify/require"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -24,9 +22,7 @@ func TestPetnamePreflight(t *testing.T) {
 }
 
 func TestGrpcPetnamePing(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -38,9 +34,7 @@ func TestGrpcPetnamePing(t *testing.T) {
 }
 
 func TestGrpcPetname(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -64,9 +58,7 @@ func TestGrpcPetname(t *testing.T) {
 }
 
 func TestGrpcPetnameNoWords(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -79,9 +71,7 @@ func TestGrpcPetnameNoWords(t *testing.T) {
 }
 
 func TestGrpcPetnameNegativeWords(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -94,9 +84,7 @@ func TestGrpcPetnameNegativeWords(t *testing.T) {
 }
 
 func TestGrpcPetnameStream(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -129,9 +117,7 @@ func TestGrpcPetnameStream(t *testing.T) {
 }
 
 func TestGrpcPetnameStreamNoWords(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -149,9 +135,7 @@ func TestGrpcPetnameStreamNoWords(t *testing.T) {
 }
 
 func TestGrpcPetnameStreamNegativeWords(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -169,9 +153,7 @@ func TestGrpcPetnameStreamNegativeWords(t *testing.T) {
 }
 
 func TestGrpcPetnameStreamNoNames(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -189,9 +171,7 @@ func TestGrpcPetnameStreamNoNames(t *testing.T) {
 }
 
 func TestGrpcPetnameStreamNegativeNames(t *testing.T) {
-	conn, err := grpc.NewClient(
-		petnameAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(petnameAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewPetnameGeneratorClient(conn)
@@ -8,9 +8,7 @@ import (
 
 	pb "github.com/guryev-vladislav/go-toolkit/servers/grpc_server/tests/proto/words"
 	"github.com/stretchr/testify/require"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -22,9 +20,7 @@ func TestWordsPreflight(t *testing.T) {
 }
 
 func TestGrpcWordsPing(t *testing.T) {
-	conn, err := grpc.NewClient(
-		wordsAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(wordsAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewWordsClient(conn)
@@ -36,9 +32,7 @@ func TestGrpcWordsPing(t *testing.T) {
 }
 
 func TestGrpcWords(t *testing.T) {
-	conn, err := grpc.NewClient(
-		wordsAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(wordsAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewWordsClient(conn)
@@ -92,9 +86,7 @@ func TestGrpcWords(t *testing.T) {
 }
 
 func TestGrpcWordsTooLarge(t *testing.T) {
-	conn, err := grpc.NewClient(
-		wordsAddress, grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := newClient(wordsAddress)
 	require.NoError(t, err)
 	defer conn.Close()
 	c := pb.NewWordsClient(conn)